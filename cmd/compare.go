@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"ghprofile/github"
+	"ghprofile/ui"
+)
+
+func runCompare(args []string) {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`Compare GitHub users side by side
+
+Usage:
+	ghprofile compare [flags] <user> <user>...
+
+Flags:
+	--format          Output format: text, json, markdown (default: text)
+	--no-style        Remove all styles from output
+	--token           GitHub token (default: $GITHUB_TOKEN or $GH_TOKEN)
+	--api             API to fetch with: rest, graphql (default: rest; graphql needs a token)`)
+	}
+	format := fs.String("format", "text", "Output format: text, json, markdown")
+	noStyle := fs.Bool("no-style", false, "Remove all styles from output")
+	token := fs.String("token", "", "GitHub token (default: $GITHUB_TOKEN or $GH_TOKEN)")
+	api := fs.String("api", "rest", "API to fetch with: rest, graphql (graphql needs a token)")
+	fs.Parse(args)
+
+	users := fs.Args()
+	if len(users) < 2 {
+		fmt.Fprintln(os.Stderr, "error: compare needs at least two usernames")
+		fs.Usage()
+		os.Exit(2)
+	}
+	checkFormat(*format)
+
+	gh := &github.Github{Client: http.DefaultClient, Token: resolveToken(*token)}
+	fetcher, err := github.NewFetcher(gh, *api)
+	if errors.Is(err, github.ErrNoToken) {
+		fmt.Fprintln(os.Stderr, "warning: --api=graphql needs a token (--token or $GITHUB_TOKEN); using REST")
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	summaries := make([]*github.Summary, len(users))
+	var wg sync.WaitGroup
+	for i, user := range users {
+		wg.Add(1)
+		go func(i int, user string) {
+			defer wg.Done()
+			p, repos, err := fetcher.FetchProfileWithRepos(ctx, user)
+			if err != nil {
+				cp, cr, cerr := github.TryLoadCache(user)
+				if cerr != nil {
+					fmt.Fprintf(os.Stderr, "warning: fetch failed for %s and no cache available: %v\n", user, err)
+					return
+				}
+				fmt.Fprintf(os.Stderr, "warning: fetch failed — using cached data for %s\n", user)
+				p, repos = cp, cr
+			} else if err := github.SaveCache(user, p, repos); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to save cache: %v\n", err)
+			}
+			s := github.Summarize(p, repos, now)
+			summaries[i] = &s
+		}(i, user)
+	}
+	wg.Wait()
+
+	var fetched []github.Summary
+	for _, s := range summaries {
+		if s != nil {
+			fetched = append(fetched, *s)
+		}
+	}
+	if len(fetched) == 0 {
+		fmt.Fprintln(os.Stderr, "error: could not load any of the users")
+		os.Exit(1)
+	}
+
+	switch *format {
+	case "json":
+		b, err := ui.RenderCompareJSON(fetched)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(b))
+	case "markdown":
+		fmt.Print(ui.RenderCompareMarkdown(fetched))
+	default:
+		fmt.Print(ui.RenderCompare(fetched, *noStyle))
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"ghprofile/ui"
+)
+
+// config holds defaults read from the config file. Flags given on the command
+// line take precedence.
+type config struct {
+	// Sections and Stats are the --sections and --stats lists.
+	Sections []string `json:"sections,omitempty"`
+	Stats    []string `json:"stats,omitempty"`
+	// Icons is the --icons set, for when auto-detection guesses wrong.
+	Icons string `json:"icons,omitempty"`
+}
+
+// defaultConfigPath returns where the config file is looked for when --config
+// isn't given: ghprofile/config.json under the user's config directory.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "ghprofile", "config.json")
+}
+
+// loadConfig reads the config file at path. A missing file is only an error
+// when required, i.e. when it was named with --config.
+func loadConfig(path string, required bool) (config, error) {
+	var cfg config
+	if path == "" {
+		return cfg, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// parseList splits a comma-separated flag value into lowercase names, checking
+// each against valid. what names the list in errors.
+func parseList(value, what string, valid []string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(valid, name) {
+			return nil, fmt.Errorf("unknown %s %q (want %s)", what, name, strings.Join(valid, ", "))
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// resolveLists parses the --sections and --stats values, using the config
+// file's list for a flag left empty.
+func resolveLists(sectionsFlag, statsFlag string, cfg config) (sections, stats []string, err error) {
+	if sectionsFlag == "" {
+		sectionsFlag = strings.Join(cfg.Sections, ",")
+	}
+	if statsFlag == "" {
+		statsFlag = strings.Join(cfg.Stats, ",")
+	}
+	if sections, err = parseList(sectionsFlag, "section", ui.Sections); err != nil {
+		return nil, nil, fmt.Errorf("--sections: %w", err)
+	}
+	if stats, err = parseList(statsFlag, "stat", ui.StatNames); err != nil {
+		return nil, nil, fmt.Errorf("--stats: %w", err)
+	}
+	return sections, stats, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseList(t *testing.T) {
+	valid := []string{"header", "stats", "repos"}
+	for _, tc := range []struct {
+		value string
+		want  []string
+		err   string
+	}{
+		{"", nil, ""},
+		{"stats, Header,,repos", []string{"stats", "header", "repos"}, ""},
+		{"stats,avatar", nil, `unknown section "avatar" (want header, stats, repos)`},
+	} {
+		got, err := parseList(tc.value, "section", valid)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("parseList(%q): expected error %q, got %v", tc.value, tc.err, err)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got, tc.want) {
+			t.Errorf("parseList(%q) = %v, %v; want %v", tc.value, got, err, tc.want)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing.json")
+	if cfg, err := loadConfig(missing, false); err != nil || cfg.Sections != nil {
+		t.Fatalf("expected a missing default config to be ignored, got %+v, %v", cfg, err)
+	}
+	if _, err := loadConfig(missing, true); err == nil {
+		t.Fatal("expected an error for a missing --config file")
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte("{"), 0o644)
+	if _, err := loadConfig(bad, false); err == nil || !strings.Contains(err.Error(), bad) {
+		t.Fatalf("expected an error naming the malformed file, got %v", err)
+	}
+
+	good := filepath.Join(dir, "config.json")
+	os.WriteFile(good, []byte(`{"sections":["header","repos"],"stats":["stars"],"icons":"ascii"}`), 0o644)
+	cfg, err := loadConfig(good, true)
+	if err != nil || !slices.Equal(cfg.Sections, []string{"header", "repos"}) || !slices.Equal(cfg.Stats, []string{"stars"}) || cfg.Icons != "ascii" {
+		t.Fatalf("unexpected config %+v, %v", cfg, err)
+	}
+}
+
+func TestResolveListsPrecedence(t *testing.T) {
+	cfg := config{Sections: []string{"header", "repos"}, Stats: []string{"stars"}}
+	for _, tc := range []struct {
+		name                    string
+		sectionsFlag, statsFlag string
+		sections, stats         []string
+	}{
+		{"config", "", "", []string{"header", "repos"}, []string{"stars"}},
+		{"flags win", "stats", "followers,forks", []string{"stats"}, []string{"followers", "forks"}},
+		{"per list", "languages", "", []string{"languages"}, []string{"stars"}},
+	} {
+		sections, stats, err := resolveLists(tc.sectionsFlag, tc.statsFlag, cfg)
+		if err != nil || !slices.Equal(sections, tc.sections) || !slices.Equal(stats, tc.stats) {
+			t.Errorf("%s: got %v %v %v; want %v %v", tc.name, sections, stats, err, tc.sections, tc.stats)
+		}
+	}
+
+	if _, _, err := resolveLists("", "", config{Stats: []string{"karma"}}); err == nil || !strings.HasPrefix(err.Error(), "--stats: unknown stat") {
+		t.Errorf("expected an unknown stat in the config to be reported, got %v", err)
+	}
+	if _, _, err := resolveLists("header,footer", "", cfg); err == nil || !strings.HasPrefix(err.Error(), "--sections: unknown section") {
+		t.Errorf("expected an unknown section to be reported, got %v", err)
+	}
+}
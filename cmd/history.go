@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"ghprofile/github"
+	"ghprofile/ui"
+)
+
+func runHistory(args []string) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`Show how a user's numbers changed across recorded snapshots
+
+Usage:
+	ghprofile history [flags] <user>
+
+Snapshots are recorded by running ghprofile with --snapshot.
+
+Flags:
+	--format          Output format: text, json (default: text)
+	--no-style        Remove all styles from output`)
+	}
+	format := fs.String("format", "text", "Output format: text, json")
+	noStyle := fs.Bool("no-style", false, "Remove all styles from output")
+	user := parseInterspersed(fs, args)
+
+	snaps := loadSnapshots(user)
+	switch *format {
+	case "json":
+		printJSON(snaps)
+	case "text":
+		fmt.Print(ui.RenderHistory(user, snaps, *noStyle))
+	default:
+		fmt.Fprintf(os.Stderr, "error: unknown --format %q (want text or json)\n", *format)
+		os.Exit(2)
+	}
+}
+
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`Show what changed for a user since an earlier snapshot
+
+Usage:
+	ghprofile diff [flags] <user>
+
+Snapshots are recorded by running ghprofile with --snapshot.
+
+Flags:
+	--since           Compare against the snapshot from this date or age (e.g. 2024-01-31, 30d) (default: 30d)
+	--format          Output format: text, json (default: text)
+	--no-style        Remove all styles from output`)
+	}
+	sinceFlag := fs.String("since", "30d", "Compare against the snapshot from this date or age (e.g. 2024-01-31, 30d)")
+	format := fs.String("format", "text", "Output format: text, json")
+	noStyle := fs.Bool("no-style", false, "Remove all styles from output")
+	user := parseInterspersed(fs, args)
+
+	since, err := github.ParseSince(*sinceFlag, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: --since: %v\n", err)
+		os.Exit(2)
+	}
+	snaps := loadSnapshots(user)
+	latest := snaps[len(snaps)-1]
+	from := github.SnapshotBefore(snaps, since)
+	if from.Time.After(since) {
+		fmt.Fprintf(os.Stderr, "warning: no snapshot that old; comparing against the oldest one (%s)\n", from.Time.Format("2006-01-02"))
+	}
+	d := github.DiffSnapshots(from, latest)
+	switch *format {
+	case "json":
+		printJSON(d)
+	case "text":
+		fmt.Print(ui.RenderDiff(user, d, *noStyle))
+	default:
+		fmt.Fprintf(os.Stderr, "error: unknown --format %q (want text or json)\n", *format)
+		os.Exit(2)
+	}
+}
+
+// parseInterspersed parses fs from args, allowing flags after the single
+// positional username, and returns that username. It exits on usage errors.
+func parseInterspersed(fs *flag.FlagSet, args []string) string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if len(positional) != 1 {
+		fmt.Fprintf(os.Stderr, "error: %s needs exactly one username\n", fs.Name())
+		fs.Usage()
+		os.Exit(2)
+	}
+	return positional[0]
+}
+
+// loadSnapshots returns the user's snapshots or exits with an explanation.
+func loadSnapshots(user string) []github.Snapshot {
+	snaps, err := github.LoadSnapshots(user)
+	if errors.Is(err, github.ErrNoSnapshots) {
+		fmt.Fprintf(os.Stderr, "error: no snapshots recorded for %s; run ghprofile -u %s --snapshot to record one\n", user, user)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	return snaps
+}
+
+func printJSON(v any) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(b))
+}
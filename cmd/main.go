@@ -2,43 +2,140 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"ghprofile/github"
 	"ghprofile/ui"
+
+	"github.com/charmbracelet/x/term"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "compare":
+			runCompare(os.Args[2:])
+			return
+		case "team":
+			runTeam(os.Args[2:])
+			return
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "history":
+			runHistory(os.Args[2:])
+			return
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "repo":
+			runRepo(os.Args[2:])
+			return
+		}
+	}
+
 	flag.Usage = func() {
 		fmt.Println(`ghprofile: Pretty GitHub profile viewer
 
 Usage:
 	ghprofile [flags]
+	ghprofile compare [flags] <user> <user>...
+	ghprofile team [flags] --file team.txt
+	ghprofile serve [flags]
+	ghprofile history [flags] <user>
+	ghprofile diff [flags] <user>
+	ghprofile repo [flags] <owner/name>
 
 Flags:
 	-u, --user        GitHub username to fetch
+	--me              Show the token owner, including private repos (needs a token)
 	-n                How many top repos to show (default: 5)
-	--no-icons        Disable icons in the output
+	--icons           Icon set: auto, nerd, emoji, ascii, none (default: auto)
+	--no-icons        Disable icons in the output (same as --icons none)
 	--no-border       Remove card border from output
 	--no-style        Remove all styles from output
+	--no-avatar       Don't draw the avatar next to the name
 	--no-demo         Do not fall back to demo data on fetch error; exit instead
 	--demo            Force demo data (skip network and cache)
+	--demo-seed       Seed for the generated demo data (default: derived from the username)
+	--demo-repos      How many repos the demo profile has (default: 8)
+	--size            Output size: small, medium, large, full (default: medium)
+	--format          Output format: text, json, markdown (default: text)
+	--starred         Show recently starred repositories
+	--activity        Show a summary of public activity over the last 30 days
+	--token           GitHub token (default: $GITHUB_TOKEN or $GH_TOKEN)
+	--year            Contribution calendar year (default: last 12 months; needs a token)
+	--api             API to fetch with: rest, graphql (default: rest; graphql needs a token)
+	--top-by          Which repos to list: pinned, stars, forks, updated (default: stars)
+	--sort            Sort the repo list by: stars, forks, watchers, issues, size, created, updated, pushed, name (overrides --top-by)
+	--order           Sort order: desc, asc (default: desc)
+	--no-forks        Hide forked repos
+	--no-archived     Hide archived repos
+	--no-templates    Hide template repos
+	--no-mirrors      Hide mirror repos
+	--lang            Only list repos whose primary language is this
+	--topic           Only list repos tagged with this topic
+	--updated-since   Only list repos pushed since a date or age (e.g. 2024-01-31, 90d, 1y)
+	--lang-bytes      Break languages down by bytes of code (one extra request per repo, cached)
+	--lang-weight     Weigh languages by: repos, stars (default: repos)
+	--top-langs       How many languages to show before grouping the rest as Other; 0 shows all (default: 8)
+	--snapshot        Record a snapshot of the numbers for history and diff
+	--record          Save API responses as fixtures in this directory (tokens scrubbed)
+	--replay          Serve API responses from fixtures in this directory, fully offline
+	--watch           Keep running and refresh on this interval (e.g. 5m), highlighting changes
+	--sections        Card sections to show, in order (default: header,info,stats,contributions,languages,pinned,repos,activity,gists,starred)
+	--stats           Stat rows to show, in order (default: followers,following,repos,private,gists,stars,forks,avg-stars)
+	--config          Config file (default: ghprofile/config.json in the user config directory)
 	-h, --help        Show this help message`)
 	}
 	userLong := flag.String("user", "", "GitHub username to fetch")
 	userShort := flag.String("u", "", "GitHub username (shorthand)")
+	me := flag.Bool("me", false, "Show the token owner, including private repos (needs a token)")
 	topN := flag.Int("n", 5, "How many top repos to show")
-	noIcons := flag.Bool("no-icons", false, "Disable icons in the output")
+	icons := flag.String("icons", "auto", "Icon set: auto, nerd, emoji, ascii, none")
+	noIcons := flag.Bool("no-icons", false, "Disable icons in the output (same as --icons none)")
 	noDemo := flag.Bool("no-demo", false, "Do not fall back to demo data on fetch error; exit instead")
 	demo := flag.Bool("demo", false, "Force demo data (skip network and cache)")
+	demoSeed := flag.Uint64("demo-seed", 0, "Seed for the generated demo data (default: derived from the username)")
+	demoRepos := flag.Int("demo-repos", 0, "How many repos the demo profile has (default: 8)")
 	noBorder := flag.Bool("no-border", false, "Remove card border from output")
 	noStyle := flag.Bool("no-style", false, "Remove all styles from output")
+	noAvatar := flag.Bool("no-avatar", false, "Don't draw the avatar next to the name")
 	size := flag.String("size", "medium", "Output size: small, medium, large, full")
+	format := flag.String("format", "text", "Output format: text, json, markdown")
+	starred := flag.Bool("starred", false, "Show recently starred repositories")
+	activity := flag.Bool("activity", false, "Show a summary of public activity over the last 30 days")
+	token := flag.String("token", "", "GitHub token (default: $GITHUB_TOKEN or $GH_TOKEN)")
+	year := flag.Int("year", 0, "Contribution calendar year (default: last 12 months; needs a token)")
+	api := flag.String("api", "rest", "API to fetch with: rest, graphql (graphql needs a token)")
+	topBy := flag.String("top-by", "stars", "Which repos to list: pinned, stars, forks, updated")
+	sortBy := flag.String("sort", "", "Sort the repo list by: "+strings.Join(github.SortKeys, ", ")+" (overrides --top-by)")
+	order := flag.String("order", "desc", "Sort order: desc, asc")
+	noForks := flag.Bool("no-forks", false, "Hide forked repos")
+	noArchived := flag.Bool("no-archived", false, "Hide archived repos")
+	noTemplates := flag.Bool("no-templates", false, "Hide template repos")
+	noMirrors := flag.Bool("no-mirrors", false, "Hide mirror repos")
+	langFilter := flag.String("lang", "", "Only list repos whose primary language is this")
+	topicFilter := flag.String("topic", "", "Only list repos tagged with this topic")
+	updatedSince := flag.String("updated-since", "", "Only list repos pushed since a date or age (e.g. 2024-01-31, 90d, 1y)")
+	langBytes := flag.Bool("lang-bytes", false, "Break languages down by bytes of code (one extra request per repo, cached)")
+	langWeight := flag.String("lang-weight", ui.LangWeightRepos, "Weigh languages by: repos, stars")
+	topLangs := flag.Int("top-langs", 8, "How many languages to show before grouping the rest as Other; 0 shows all")
+	snapshot := flag.Bool("snapshot", false, "Record a snapshot of the numbers for history and diff")
+	record := flag.String("record", "", "Save API responses as fixtures in this directory (tokens scrubbed)")
+	replay := flag.String("replay", "", "Serve API responses from fixtures in this directory, fully offline")
+	watch := flag.Duration("watch", 0, "Keep running and refresh on this interval (e.g. 5m), highlighting changes")
+	sectionsFlag := flag.String("sections", "", "Card sections to show, in order (e.g. header,stats,repos)")
+	statsFlag := flag.String("stats", "", "Stat rows to show, in order (e.g. followers,stars)")
+	configFile := flag.String("config", "", "Config file (default: ghprofile/config.json in the user config directory)")
 	flag.Parse()
 
 	user := *userLong
@@ -47,28 +144,308 @@ Flags:
 	}
 
 	// If no user provided and not running in demo mode, require --user
-	if user == "" && !*demo {
-		fmt.Fprintln(os.Stderr, "error: --user is required unless --demo is set")
+	if user == "" && !*demo && !*me {
+		fmt.Fprintln(os.Stderr, "error: --user is required unless --demo or --me is set")
 		flag.Usage()
 		os.Exit(2)
 	}
 
-	gh := &github.Github{Client: http.DefaultClient}
+	switch *topBy {
+	case "pinned", "stars", "forks", "updated":
+	default:
+		fmt.Fprintf(os.Stderr, "error: unknown --top-by %q (want pinned, stars, forks or updated)\n", *topBy)
+		os.Exit(2)
+	}
+
+	if *sortBy != "" && !github.IsSortKey(*sortBy) {
+		fmt.Fprintf(os.Stderr, "error: unknown --sort %q (want one of %s)\n", *sortBy, strings.Join(github.SortKeys, ", "))
+		os.Exit(2)
+	}
+	if *order != "asc" && *order != "desc" {
+		fmt.Fprintf(os.Stderr, "error: unknown --order %q (want asc or desc)\n", *order)
+		os.Exit(2)
+	}
+	if *langWeight != ui.LangWeightRepos && *langWeight != ui.LangWeightStars {
+		fmt.Fprintf(os.Stderr, "error: unknown --lang-weight %q (want repos or stars)\n", *langWeight)
+		os.Exit(2)
+	}
+	if *langBytes && *langWeight != ui.LangWeightRepos {
+		fmt.Fprintln(os.Stderr, "error: --lang-weight cannot be combined with --lang-bytes")
+		os.Exit(2)
+	}
+	if *topLangs < 0 {
+		fmt.Fprintln(os.Stderr, "error: --top-langs must not be negative")
+		os.Exit(2)
+	}
+	listBy := *topBy
+	if *sortBy != "" {
+		listBy = *sortBy
+	}
+
+	filter := github.RepoFilter{
+		NoForks:     *noForks,
+		NoArchived:  *noArchived,
+		NoTemplates: *noTemplates,
+		NoMirrors:   *noMirrors,
+		Language:    *langFilter,
+		Topic:       *topicFilter,
+	}
+	if *updatedSince != "" {
+		since, err := github.ParseSince(*updatedSince, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: --updated-since: %v\n", err)
+			os.Exit(2)
+		}
+		filter.UpdatedSince = since
+	}
+
+	checkFormat(*format)
+	cfgPath := *configFile
+	if cfgPath == "" {
+		cfgPath = defaultConfigPath()
+	}
+	cfg, err := loadConfig(cfgPath, *configFile != "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: config: %v\n", err)
+		os.Exit(2)
+	}
+	if *format == "json" && (*sectionsFlag != "" || *statsFlag != "") {
+		fmt.Fprintln(os.Stderr, "error: --sections and --stats only apply to --format text and markdown")
+		os.Exit(2)
+	}
+	sections, stats, err := resolveLists(*sectionsFlag, *statsFlag, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+	// JSON has every field, so the config file's lists, meant for the cards,
+	// don't apply to it.
+	if *format == "json" {
+		sections, stats = nil, nil
+	}
+	iconSet := useIcons(*icons, *noIcons, cfg, *format == "text" && term.IsTerminal(os.Stdout.Fd()))
+	// Listing a section that needs extra requests asks for them.
+	if slices.Contains(sections, "activity") {
+		*activity = true
+	}
+	if slices.Contains(sections, "starred") {
+		*starred = true
+	}
+	if *watch != 0 {
+		switch {
+		case *watch < minWatchInterval:
+			fmt.Fprintf(os.Stderr, "error: --watch interval must be at least %s\n", minWatchInterval)
+			os.Exit(2)
+		case *format != "text":
+			fmt.Fprintln(os.Stderr, "error: --watch only supports --format text")
+			os.Exit(2)
+		case *demo:
+			fmt.Fprintln(os.Stderr, "error: --watch cannot be combined with --demo")
+			os.Exit(2)
+		}
+	}
+
+	opts := ui.Options{
+		TopN:       *topN,
+		ShowIcons:  iconSet != ui.IconsNone,
+		Icons:      iconSet,
+		NoBorder:   *noBorder,
+		NoStyle:    *noStyle,
+		Size:       *size,
+		TopBy:      listBy,
+		Ascending:  *order == "asc",
+		Filter:     filter,
+		LangBytes:  *langBytes,
+		LangWeight: *langWeight,
+		TopLangs:   *topLangs,
+		Sections:   sections,
+		Stats:      stats,
+		Activity:   *activity,
+	}
+	// showSection reports whether --sections lists name, or is left empty.
+	showSection := func(name string) bool {
+		return len(sections) == 0 || slices.Contains(sections, name)
+	}
+	// prepare trims p to the sections asked for.
+	prepare := func(p *github.Profile) {
+		// A cached profile may carry sections from an earlier run with other flags.
+		if !*starred {
+			p.Starred = nil
+		}
+		if !*activity {
+			p.Events = nil
+		}
+		if !showSection("gists") {
+			p.Gists = nil
+		}
+		if !showSection("contributions") || (p.Contributions != nil && p.Contributions.Year != *year) {
+			p.Contributions = nil
+		}
+	}
+	render := func(p *github.Profile, repos []github.Repo) {
+		prepare(p)
+		switch *format {
+		case "json":
+			if err := ui.PrintJSON(p, repos); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+				os.Exit(1)
+			}
+		case "markdown":
+			ui.PrintMarkdown(p, repos, opts)
+		default:
+			ui.PrintProfile(p, repos, opts)
+		}
+	}
+
+	*token = resolveToken(*token)
+	if listBy == "pinned" && *token == "" {
+		fmt.Fprintln(os.Stderr, "warning: --top-by=pinned needs a token (--token or $GITHUB_TOKEN); listing by stars")
+	}
+	if *year != 0 && *token == "" {
+		fmt.Fprintln(os.Stderr, "warning: --year needs a token (--token or $GITHUB_TOKEN); skipping contribution calendar")
+	}
+
+	gh := newGithub(*token, *record, *replay)
+	fetcher, err := github.NewFetcher(gh, *api)
+	if errors.Is(err, github.ErrNoToken) {
+		fmt.Fprintln(os.Stderr, "warning: --api=graphql needs a token (--token or $GITHUB_TOKEN); using REST")
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
 
+	demoConfig := github.DemoProfileConfig{Username: user, Seed: *demoSeed, Repos: *demoRepos}
 	if *demo {
-		p, repos := github.DemoProfile(github.DemoProfileConfig{Username: user})
-		showIcons := !*noIcons
-		ui.PrintProfile(p, repos, *topN, showIcons, *noBorder, *noStyle, *size)
+		p, repos := github.DemoProfile(demoConfig)
+		opts.Now = demoConfig.ReferenceTime()
+		render(p, repos)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	p, repos, err := gh.FetchProfileWithRepos(ctx, user)
+	if *me {
+		if gh.Token == "" {
+			fmt.Fprintln(os.Stderr, "error: --me needs a token (--token or $GITHUB_TOKEN)")
+			os.Exit(2)
+		}
+		owner, err := gh.TokenOwner(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: could not look up the token owner: %v\n", err)
+			os.Exit(1)
+		}
+		if user != "" && !strings.EqualFold(user, owner) {
+			fmt.Fprintf(os.Stderr, "error: --me shows the token owner, %s, not --user %s\n", owner, user)
+			os.Exit(2)
+		}
+		user = owner
+		// Private repos are only listed over REST.
+		fetcher = github.OwnFetcher{Github: gh}
+	} else if gh.Token != "" {
+		// Asking for the token owner by name shows their private repos too.
+		// Other commands and serve stay public-only. The owner is remembered per
+		// token, so this only costs a request the first time.
+		if owner, err := gh.TokenOwner(ctx); err == nil && strings.EqualFold(owner, user) {
+			fetcher = github.OwnFetcher{Github: gh}
+		}
+	}
+
+	// warnings receives non-fatal fetch problems. Watch mode discards them, as
+	// writing to the terminal would garble the redrawn screen.
+	var warnings io.Writer = os.Stderr
+	// load fetches the profile with every section the flags ask for, then caches
+	// it and records a snapshot if requested.
+	load := func(ctx context.Context) (*github.Profile, []github.Repo, error) {
+		p, repos, err := fetcher.FetchProfileWithRepos(ctx, user)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, cached, cerr := github.TryLoadCache(user); cerr == nil {
+			github.ReuseLanguages(repos, cached)
+			github.ReuseParents(repos, cached)
+		}
+		// The REST repo lists leave out which repo a fork was forked from.
+		if perr := gh.FillParents(ctx, repos); perr != nil {
+			fmt.Fprintf(warnings, "warning: failed to look up some fork parents: %v\n", perr)
+		}
+		if *langBytes {
+			if lerr := gh.FillLanguages(ctx, repos); lerr != nil {
+				fmt.Fprintf(warnings, "warning: failed to fetch some repo languages: %v\n", lerr)
+			}
+		}
+		if p.PublicGistsAmount > 0 && showSection("gists") {
+			if gists, gerr := gh.GetGists(ctx, user); gerr != nil {
+				fmt.Fprintf(warnings, "warning: failed to fetch gists: %v\n", gerr)
+			} else {
+				p.Gists = gists
+			}
+		}
+		if *starred {
+			if st, serr := gh.GetStarred(ctx, user); serr != nil {
+				fmt.Fprintf(warnings, "warning: failed to fetch starred repos: %v\n", serr)
+			} else {
+				p.Starred = st
+			}
+		}
+		if gh.Token != "" && p.Pinned == nil {
+			if pinned, perr := gh.GetPinned(ctx, user); perr != nil {
+				fmt.Fprintf(warnings, "warning: failed to fetch pinned repos: %v\n", perr)
+			} else {
+				p.Pinned = pinned
+			}
+		}
+		if gh.Token != "" && showSection("contributions") {
+			if cal, cerr := gh.GetContributionCalendar(ctx, user, *year); cerr != nil {
+				fmt.Fprintf(warnings, "warning: failed to fetch contribution calendar: %v\n", cerr)
+			} else {
+				p.Contributions = cal
+			}
+		}
+		if *activity {
+			if events, eerr := gh.GetEvents(ctx, user); eerr != nil {
+				fmt.Fprintf(warnings, "warning: failed to fetch activity: %v\n", eerr)
+			} else {
+				p.Events = events
+			}
+		}
+		if err := github.SaveCache(user, p, repos); err != nil {
+			fmt.Fprintf(warnings, "warning: failed to save cache: %v\n", err)
+		}
+		if *snapshot {
+			if err := github.AppendSnapshot(user, github.NewSnapshot(p, repos, time.Now())); err != nil {
+				fmt.Fprintf(warnings, "warning: failed to record snapshot: %v\n", err)
+			}
+		}
+		return p, repos, nil
+	}
+
+	if *watch > 0 {
+		warnings = io.Discard
+		err := ui.Watch(func() (*github.Profile, []github.Repo, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+			p, repos, err := load(ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+			prepare(p)
+			return p, repos, nil
+		}, *watch, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	p, repos, err := load(ctx)
+	demoData := false
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "warning: fetch failed: %v\n", err)
-		if strings.Contains(err.Error(), "rate limit") || strings.Contains(err.Error(), "API rate limit") {
+		var rlErr *github.RateLimitError
+		if errors.As(err, &rlErr) && gh.Token == "" {
+			fmt.Fprintln(os.Stderr, "hint: pass --token or set $GITHUB_TOKEN to raise the rate limit")
 		}
 		if *noDemo {
 			if cp, cr, cerr := github.TryLoadCache(user); cerr == nil {
@@ -84,15 +461,101 @@ Flags:
 				p, repos = cp, cr
 			} else {
 				fmt.Fprintf(os.Stderr, "warning: fetch failed (%v) — falling back to demo data for %s\n", err, user)
-				p, repos = github.DemoProfile(github.DemoProfileConfig{Username: user})
+				demoConfig.Username = user
+				p, repos = github.DemoProfile(demoConfig)
+				opts.Now = demoConfig.ReferenceTime()
+				demoData = true
 			}
 		}
-	} else {
-		if err := github.SaveCache(user, p, repos); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to save cache: %v\n", err)
-		}
 	}
 
-	showIcons := !*noIcons
-	ui.PrintProfile(p, repos, *topN, showIcons, *noBorder, *noStyle, *size)
+	if !*noAvatar && !demoData && *format == "text" && !*noStyle && term.IsTerminal(os.Stdout.Fd()) {
+		opts.Avatar = loadAvatar(ctx, gh, user, p.AvatarURL)
+		opts.ImageProtocol = ui.DetectImageProtocol()
+	}
+	render(p, repos)
+}
+
+// newGithub returns the API client for commands taking --record and --replay,
+// which record or replay fixtures when given a directory. Its ETag cache makes
+// repeated requests, as in watch mode, conditional. It exits when both
+// directories are given.
+func newGithub(token, record, replay string) *github.Github {
+	if record != "" && replay != "" {
+		fmt.Fprintln(os.Stderr, "error: --record and --replay cannot be combined")
+		os.Exit(2)
+	}
+	return &github.Github{Client: httpClient(record, replay), Token: resolveToken(token), ETags: github.NewETagCache()}
+}
+
+// httpClient returns the client for API requests, recording or replaying
+// fixtures when a directory is given.
+func httpClient(record, replay string) *http.Client {
+	switch {
+	case replay != "":
+		return &http.Client{Transport: &github.ReplayTransport{Dir: replay}}
+	case record != "":
+		return &http.Client{Transport: &github.RecordingTransport{Dir: record}}
+	}
+	return http.DefaultClient
+}
+
+// loadAvatar returns the user's avatar, or nil when it can't be had. The
+// avatar is decoration, so failures are not reported.
+func loadAvatar(ctx context.Context, gh *github.Github, user, avatarURL string) *ui.Avatar {
+	data, err := gh.GetAvatar(ctx, user, avatarURL)
+	if err != nil {
+		return nil
+	}
+	a, err := ui.DecodeAvatar(data)
+	if err != nil {
+		return nil
+	}
+	return a
+}
+
+// useIcons returns the icon set picked by --icons, --no-icons, the config file
+// or detection, in that order. terminal says whether the output is text on a
+// terminal, the only case worth probing the terminal and fonts for. It exits
+// on an unknown set.
+func useIcons(name string, noIcons bool, cfg config, terminal bool) string {
+	switch {
+	case noIcons:
+		name = ui.IconsNone
+	case name == "auto" && cfg.Icons != "":
+		name = cfg.Icons
+	}
+	if name == "auto" {
+		name = ui.DetectIconSet(terminal)
+	}
+	if !slices.Contains(ui.IconSets, name) {
+		fmt.Fprintf(os.Stderr, "error: unknown icon set %q (want auto, %s)\n", name, strings.Join(ui.IconSets, ", "))
+		os.Exit(2)
+	}
+	return name
+}
+
+// minWatchInterval keeps --watch from hammering the API.
+const minWatchInterval = 10 * time.Second
+
+// resolveToken returns the --token value, falling back to $GITHUB_TOKEN and then
+// $GH_TOKEN (as set by the gh CLI).
+func resolveToken(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if t := os.Getenv("GITHUB_TOKEN"); t != "" {
+		return t
+	}
+	return os.Getenv("GH_TOKEN")
+}
+
+// checkFormat exits with a usage error unless format is a supported output format.
+func checkFormat(format string) {
+	switch format {
+	case "text", "json", "markdown":
+	default:
+		fmt.Fprintf(os.Stderr, "error: unknown --format %q (want text, json or markdown)\n", format)
+		os.Exit(2)
+	}
 }
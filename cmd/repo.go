@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"ghprofile/github"
+	"ghprofile/ui"
+
+	"github.com/charmbracelet/x/term"
+)
+
+func runRepo(args []string) {
+	fs := flag.NewFlagSet("repo", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`Show a card for one GitHub repository
+
+Usage:
+	ghprofile repo [flags] <owner/name>
+
+Flags:
+	--format          Output format: text, json, markdown (default: text)
+	--size            Output size: small, medium, large, full (default: medium)
+	--icons           Icon set: auto, nerd, emoji, ascii, none (default: auto)
+	--no-icons        Disable icons in the output (same as --icons none)
+	--no-border       Remove card border from output
+	--no-style        Remove all styles from output
+	--top-langs       How many languages to show before grouping the rest as Other; 0 shows all (default: 8)
+	--token           GitHub token (default: $GITHUB_TOKEN or $GH_TOKEN)
+	--record          Save API responses as fixtures in this directory (tokens scrubbed)
+	--replay          Serve API responses from fixtures in this directory, fully offline`)
+	}
+	format := fs.String("format", "text", "Output format: text, json, markdown")
+	size := fs.String("size", "medium", "Output size: small, medium, large, full")
+	icons := fs.String("icons", "auto", "Icon set: auto, nerd, emoji, ascii, none")
+	noIcons := fs.Bool("no-icons", false, "Disable icons in the output (same as --icons none)")
+	noBorder := fs.Bool("no-border", false, "Remove card border from output")
+	noStyle := fs.Bool("no-style", false, "Remove all styles from output")
+	topLangs := fs.Int("top-langs", 8, "How many languages to show before grouping the rest as Other; 0 shows all")
+	token := fs.String("token", "", "GitHub token (default: $GITHUB_TOKEN or $GH_TOKEN)")
+	record := fs.String("record", "", "Save API responses as fixtures in this directory (tokens scrubbed)")
+	replay := fs.String("replay", "", "Serve API responses from fixtures in this directory, fully offline")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "error: repo needs one repository as owner/name")
+		fs.Usage()
+		os.Exit(2)
+	}
+	name := fs.Arg(0)
+	if _, _, err := github.ParseRepoName(name); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+	checkFormat(*format)
+	if *topLangs < 0 {
+		fmt.Fprintln(os.Stderr, "error: --top-langs must not be negative")
+		os.Exit(2)
+	}
+	cfg, err := loadConfig(defaultConfigPath(), false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: config: %v\n", err)
+		os.Exit(2)
+	}
+	iconSet := useIcons(*icons, *noIcons, cfg, *format == "text" && term.IsTerminal(os.Stdout.Fd()))
+
+	gh := newGithub(*token, *record, *replay)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	d, err := gh.FetchRepoDetail(ctx, name)
+	if d == nil {
+		fmt.Fprintf(os.Stderr, "error: could not fetch %s: %v\n", name, err)
+		var rlErr *github.RateLimitError
+		if errors.As(err, &rlErr) && gh.Token == "" {
+			fmt.Fprintln(os.Stderr, "hint: pass --token or set $GITHUB_TOKEN to raise the rate limit")
+		}
+		os.Exit(1)
+	}
+	// The rest of the card is optional; say what's missing and show the card.
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			fmt.Fprintf(os.Stderr, "warning: failed to fetch %v\n", err)
+		}
+	}
+
+	switch *format {
+	case "json":
+		b, err := ui.RenderRepoJSON(d)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(b))
+	case "markdown":
+		fmt.Print(ui.RenderRepoMarkdown(d, ui.Options{TopLangs: *topLangs}))
+	default:
+		ui.PrintRepo(d, ui.Options{
+			ShowIcons: iconSet != ui.IconsNone,
+			Icons:     iconSet,
+			NoBorder:  *noBorder,
+			NoStyle:   *noStyle,
+			Size:      *size,
+			TopLangs:  *topLangs,
+		})
+	}
+}
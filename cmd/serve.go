@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"ghprofile/github"
+	"ghprofile/server"
+)
+
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`Serve profile cards and JSON over HTTP
+
+Usage:
+	ghprofile serve [flags]
+
+Routes:
+	/u/{user}.json    Profile and repos as JSON
+	/u/{user}.svg     Stats card as an SVG image
+	/u/{user}.md      Profile as Markdown
+	/u/{user}.txt     Profile as plain text
+	/healthz          Liveness check
+
+Flags:
+	--addr            Address to listen on (default: :8080)
+	--ttl             How long a fetched profile is served before refetching (default: 30m)
+	--token           GitHub token (default: $GITHUB_TOKEN or $GH_TOKEN)
+	--api             API to fetch with: rest, graphql (default: rest; graphql needs a token)`)
+	}
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	ttl := fs.Duration("ttl", github.CacheExpire, "How long a fetched profile is served before refetching")
+	token := fs.String("token", "", "GitHub token (default: $GITHUB_TOKEN or $GH_TOKEN)")
+	api := fs.String("api", "rest", "API to fetch with: rest, graphql (graphql needs a token)")
+	fs.Parse(args)
+
+	gh := &github.Github{Client: http.DefaultClient, Token: resolveToken(*token)}
+	fetcher, err := github.NewFetcher(gh, *api)
+	if errors.Is(err, github.ErrNoToken) {
+		fmt.Fprintln(os.Stderr, "warning: --api=graphql needs a token (--token or $GITHUB_TOKEN); using REST")
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+	if gh.Token == "" {
+		fmt.Fprintln(os.Stderr, "warning: no token set; the unauthenticated rate limit is 60 requests per hour")
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(fetcher, *ttl).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("ghprofile serving on %s", *addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"ghprofile/github"
+	"ghprofile/ui"
+)
+
+func runTeam(args []string) {
+	fs := flag.NewFlagSet("team", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(`Rank a team of GitHub users on a leaderboard
+
+Usage:
+	ghprofile team [flags] --file team.txt
+	ghprofile team [flags] --org acme --team platform
+	ghprofile team [flags] <user> <user>...
+
+Flags:
+	--file            File with one username per line (# starts a comment)
+	--org, --team     Use the members of an organization team (needs a token)
+	--by              Rank by: stars, followers, contributions, repos (default: stars; contributions needs a token)
+	--concurrency     How many users to fetch at once (default: 4)
+	--format          Output format: text, json, markdown (default: text)
+	--no-style        Remove all styles from output
+	--token           GitHub token (default: $GITHUB_TOKEN or $GH_TOKEN)
+	--api             API to fetch with: rest, graphql (default: rest; graphql needs a token)`)
+	}
+	file := fs.String("file", "", "File with one username per line")
+	org := fs.String("org", "", "Organization of --team")
+	team := fs.String("team", "", "Team slug within --org")
+	by := fs.String("by", "stars", "Rank by: "+strings.Join(github.TeamMetrics, ", "))
+	concurrency := fs.Int("concurrency", github.TeamConcurrency, "How many users to fetch at once")
+	format := fs.String("format", "text", "Output format: text, json, markdown")
+	noStyle := fs.Bool("no-style", false, "Remove all styles from output")
+	token := fs.String("token", "", "GitHub token (default: $GITHUB_TOKEN or $GH_TOKEN)")
+	api := fs.String("api", "rest", "API to fetch with: rest, graphql (graphql needs a token)")
+	fs.Parse(args)
+
+	checkFormat(*format)
+	if !github.IsTeamMetric(*by) {
+		fmt.Fprintf(os.Stderr, "error: unknown --by %q (want one of %s)\n", *by, strings.Join(github.TeamMetrics, ", "))
+		os.Exit(2)
+	}
+	if (*org == "") != (*team == "") {
+		fmt.Fprintln(os.Stderr, "error: --org and --team must be used together")
+		os.Exit(2)
+	}
+
+	gh := &github.Github{Client: http.DefaultClient, Token: resolveToken(*token)}
+	if *by == "contributions" && gh.Token == "" {
+		fmt.Fprintln(os.Stderr, "error: --by contributions needs a token (--token or $GITHUB_TOKEN)")
+		os.Exit(2)
+	}
+	fetcher, err := github.NewFetcher(gh, *api)
+	if errors.Is(err, github.ErrNoToken) {
+		fmt.Fprintln(os.Stderr, "warning: --api=graphql needs a token (--token or $GITHUB_TOKEN); using REST")
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	users := fs.Args()
+	if *file != "" {
+		fromFile, err := readTeamFile(*file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		users = append(users, fromFile...)
+	}
+	if *org != "" {
+		members, err := gh.GetTeamMembers(ctx, *org, *team)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: could not list %s/%s: %v\n", *org, *team, err)
+			os.Exit(1)
+		}
+		users = append(users, members...)
+	}
+	users = dedupe(users)
+	if len(users) == 0 {
+		fmt.Fprintln(os.Stderr, "error: team needs usernames from --file, --org/--team or arguments")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	_, graphQL := fetcher.(github.GraphQLFetcher)
+	if rl, err := gh.GetRateLimit(ctx); err == nil && rl.Remaining < estimateRequests(users, graphQL, gh.Token != "") {
+		fmt.Fprintf(os.Stderr, "warning: only %d API requests left until %s; some users may fall back to cached data\n", rl.Remaining, rl.Reset.Local().Format("15:04"))
+	}
+
+	now := time.Now()
+	summaries := make([]*github.Summary, len(users))
+	errs := github.FetchEach(ctx, users, *concurrency, func(ctx context.Context, i int, user string) error {
+		p, repos, err := fetcher.FetchProfileWithRepos(ctx, user)
+		if err != nil {
+			return err
+		}
+		if gh.Token != "" {
+			if cal, cerr := gh.GetContributionCalendar(ctx, user, 0); cerr != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to fetch contributions for %s: %v\n", user, cerr)
+			} else {
+				p.Contributions = cal
+			}
+		}
+		if err := github.SaveCache(user, p, repos); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to save cache: %v\n", err)
+		}
+		s := github.Summarize(p, repos, now)
+		summaries[i] = &s
+		return nil
+	})
+
+	var members []github.Summary
+	var limited *github.RateLimitError
+	for i, user := range users {
+		if errs[i] != nil {
+			errors.As(errs[i], &limited)
+			cp, cr, cerr := github.TryLoadCache(user)
+			if cerr != nil {
+				fmt.Fprintf(os.Stderr, "warning: skipping %s: %v\n", user, errs[i])
+				continue
+			}
+			s := github.Summarize(cp, cr, now)
+			summaries[i] = &s
+		}
+		members = append(members, *summaries[i])
+	}
+	if limited != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v; used cached data where available\n", limited)
+	}
+	if len(members) == 0 {
+		fmt.Fprintln(os.Stderr, "error: could not load any team member")
+		os.Exit(1)
+	}
+
+	github.RankTeam(members, *by)
+	switch *format {
+	case "json":
+		b, err := ui.RenderLeaderboardJSON(members, *by)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(b))
+	case "markdown":
+		fmt.Print(ui.RenderLeaderboardMarkdown(members, *by))
+	default:
+		fmt.Print(ui.RenderLeaderboard(members, *by, *noStyle))
+	}
+}
+
+// readTeamFile reads usernames, one per line. Blank lines and text after # are
+// ignored, as is a leading @.
+func readTeamFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var users []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		line = strings.TrimPrefix(strings.TrimSpace(line), "@")
+		if line != "" {
+			users = append(users, line)
+		}
+	}
+	return users, sc.Err()
+}
+
+// dedupe drops repeated usernames, ignoring case, keeping the first spelling.
+func dedupe(users []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, u := range users {
+		key := strings.ToLower(u)
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// estimateRequests estimates the API requests fetching users costs: the profile
+// and one per 100 repos, the GraphQL query carrying the profile along with the
+// first page, plus the contribution calendar when there is a token. Repo counts
+// come from the cache; uncached users are assumed to have one page.
+func estimateRequests(users []string, graphQL, contributions bool) int {
+	n := 0
+	for _, user := range users {
+		pages := 1
+		if p, _, err := github.TryLoadCache(user); err == nil {
+			pages = p.PublicReposAmount/100 + 1
+		}
+		n += pages
+		if !graphQL {
+			n++
+		}
+		if contributions {
+			n++
+		}
+	}
+	return n
+}
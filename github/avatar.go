@@ -0,0 +1,90 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	// AvatarExpire is how long a downloaded avatar is reused before it is fetched again.
+	AvatarExpire = 24 * time.Hour
+	// AvatarSize is the width and height in pixels requested from the avatar service.
+	AvatarSize = 96
+)
+
+// AvatarPath returns where the user's avatar is cached.
+func AvatarPath(user string) (string, error) {
+	base, err := cacheDir("avatars")
+	if err != nil {
+		return "", err
+	}
+	return base + "/" + user, nil
+}
+
+// GetAvatar returns the user's avatar image (PNG or JPEG). A copy cached on disk
+// is used while younger than AvatarExpire, and as a fallback when the download
+// fails.
+func (gh *Github) GetAvatar(ctx context.Context, user, avatarURL string) ([]byte, error) {
+	path, err := AvatarPath(user)
+	if err != nil {
+		return nil, err
+	}
+	if fi, err := os.Stat(path); err == nil && time.Since(fi.ModTime()) < AvatarExpire {
+		if b, err := os.ReadFile(path); err == nil {
+			return b, nil
+		}
+	}
+	b, err := gh.downloadAvatar(ctx, avatarURL)
+	if err != nil {
+		if cached, cerr := os.ReadFile(path); cerr == nil {
+			return cached, nil
+		}
+		return nil, err
+	}
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// downloadAvatar fetches the avatar at AvatarSize. Unlike API requests it is
+// sent without the token, as the avatar host doesn't need one.
+func (gh *Github) downloadAvatar(ctx context.Context, avatarURL string) ([]byte, error) {
+	if avatarURL == "" {
+		return nil, fmt.Errorf("no avatar URL")
+	}
+	u, err := url.Parse(avatarURL)
+	if err != nil {
+		return nil, fmt.Errorf("avatar URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("s", fmt.Sprint(AvatarSize))
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("request: %w", err)
+	}
+	req.Header.Set("User-Agent", "ghprofile-client")
+	client := gh.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{Method: req.Method, URL: req.URL.String(), StatusCode: resp.StatusCode}
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	return b, nil
+}
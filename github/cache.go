@@ -24,15 +24,27 @@ type cacheEntry struct {
 	Repos   []Repo   `json:"repos"`
 }
 
-func CachePath(user string) (string, error) {
+// cacheDir returns (and creates) the ghprofile cache directory, or sub inside it.
+func cacheDir(sub string) (string, error) {
 	dir := os.Getenv("XDG_CACHE_HOME")
 	if dir == "" {
 		dir = os.Getenv("HOME") + "/.cache"
 	}
 	base := dir + "/ghprofile"
+	if sub != "" {
+		base += "/" + sub
+	}
 	if err := os.MkdirAll(base, 0o755); err != nil {
 		return "", err
 	}
+	return base, nil
+}
+
+func CachePath(user string) (string, error) {
+	base, err := cacheDir("")
+	if err != nil {
+		return "", err
+	}
 	return base + "/" + user + CacheFileSuffix, nil
 }
 
@@ -46,7 +58,8 @@ func SaveCache(user string, p *Profile, repos []Repo) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, b, 0o644)
+	// Profiles fetched with a token may include private repos.
+	return os.WriteFile(path, b, 0o600)
 }
 
 func TryLoadCache(user string) (*Profile, []Repo, error) {
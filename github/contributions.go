@@ -0,0 +1,121 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Contribution levels as reported by GitHub, from no contributions to the top quartile.
+const (
+	LevelNone           = "NONE"
+	LevelFirstQuartile  = "FIRST_QUARTILE"
+	LevelSecondQuartile = "SECOND_QUARTILE"
+	LevelThirdQuartile  = "THIRD_QUARTILE"
+	LevelFourthQuartile = "FOURTH_QUARTILE"
+)
+
+type ContributionCalendar struct {
+	TotalContributions int                `json:"totalContributions"`
+	Weeks              []ContributionWeek `json:"weeks"`
+	// Year is the year asked for, 0 for the last 12 months, so a cached
+	// calendar can be told apart from one for another --year.
+	Year int `json:"year,omitempty"`
+}
+
+type ContributionWeek struct {
+	ContributionDays []ContributionDay `json:"contributionDays"`
+}
+
+type ContributionDay struct {
+	Date              string `json:"date"`
+	Weekday           int    `json:"weekday"`
+	ContributionCount int    `json:"contributionCount"`
+	ContributionLevel string `json:"contributionLevel"`
+}
+
+// Days returns every day of the calendar in date order.
+func (c *ContributionCalendar) Days() []ContributionDay {
+	var days []ContributionDay
+	for _, w := range c.Weeks {
+		days = append(days, w.ContributionDays...)
+	}
+	return days
+}
+
+// Streaks returns the current and longest runs of consecutive days with at least
+// one contribution. The current streak ends on the last day of the calendar, or
+// the day before it when the last day (usually today) has no contributions yet.
+func (c *ContributionCalendar) Streaks() (current, longest int) {
+	days := c.Days()
+	run := 0
+	for _, d := range days {
+		if d.ContributionCount > 0 {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	i := len(days) - 1
+	if i >= 0 && days[i].ContributionCount == 0 {
+		i--
+	}
+	for ; i >= 0 && days[i].ContributionCount > 0; i-- {
+		current++
+	}
+	return current, longest
+}
+
+const contributionsQuery = `query($login: String!, $from: DateTime, $to: DateTime) {
+  user(login: $login) {
+    contributionsCollection(from: $from, to: $to) {
+      contributionCalendar {
+        totalContributions
+        weeks {
+          contributionDays { date weekday contributionCount contributionLevel }
+        }
+      }
+    }
+  }
+}`
+
+// GetContributionCalendar fetches the contribution graph for year, or for the
+// last twelve months when year is 0. It needs a token.
+func (gh *Github) GetContributionCalendar(ctx context.Context, username string, year int) (*ContributionCalendar, error) {
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+	vars := map[string]any{"login": username}
+	if year != 0 {
+		from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(year, time.December, 31, 23, 59, 59, 0, time.UTC)
+		if now := time.Now().UTC(); to.After(now) {
+			to = now
+		}
+		if from.After(to) {
+			return nil, fmt.Errorf("year %d is in the future", year)
+		}
+		vars["from"] = from.Format(time.RFC3339)
+		vars["to"] = to.Format(time.RFC3339)
+	}
+	var data struct {
+		User *struct {
+			ContributionsCollection struct {
+				ContributionCalendar ContributionCalendar `json:"contributionCalendar"`
+			} `json:"contributionsCollection"`
+		} `json:"user"`
+	}
+	if err := gh.graphqlReused(ctx, contributionsQuery, vars, &data); err != nil {
+		return nil, err
+	}
+	if data.User == nil {
+		return nil, fmt.Errorf("github: user %q not found", username)
+	}
+	cal := data.User.ContributionsCollection.ContributionCalendar
+	cal.Year = year
+	return &cal, nil
+}
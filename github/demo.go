@@ -1,44 +1,446 @@
 package github
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+)
 
+// DemoProfileConfig shapes the data DemoProfile generates. The zero value gives
+// a plausible mid-sized profile; every field only needs setting to change that.
 type DemoProfileConfig struct {
 	Username string
+	// Seed makes the data reproducible: the same config always generates the
+	// same profile. 0 derives the seed from Username.
+	Seed uint64
+	// Repos is how many repos to generate (default 8).
+	Repos int
+	// Languages is the primary-language mix, most used first. Repos pick earlier
+	// languages more often. Defaults to a broad mix.
+	Languages []string
+	// MaxStars is the star count of the most starred repo (default 800). The
+	// others fall off along a long tail, as real profiles do.
+	MaxStars int
+	// Followers defaults to a figure in proportion to the stars.
+	Followers int
+	// Activity is the average number of public events per week (default 12);
+	// it also drives the contribution calendar. Negative means no activity.
+	Activity int
+	// Now is the reference time for generated dates. It defaults to a date
+	// derived from the seed, so a profile is the same on any day. See
+	// ReferenceTime.
+	Now time.Time
 }
 
+// demoEpoch is the earliest reference time derived from a seed.
+var demoEpoch = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// ReferenceTime returns the time the generated dates are relative to: Now, or
+// a date within two years of demoEpoch picked by the seed.
+func (cfg DemoProfileConfig) ReferenceTime() time.Time {
+	if !cfg.Now.IsZero() {
+		return cfg.Now
+	}
+	return demoEpoch.AddDate(0, 0, int(cfg.seed()%731))
+}
+
+// seed returns Seed, or one derived from the username when it is 0.
+func (cfg DemoProfileConfig) seed() uint64 {
+	if cfg.Seed != 0 {
+		return cfg.Seed
+	}
+	uname := cfg.Username
+	if uname == "" {
+		uname = "demo"
+	}
+	h := fnv.New64a()
+	h.Write([]byte(uname))
+	return h.Sum64()
+}
+
+var (
+	demoLanguages = []string{"Go", "TypeScript", "Rust", "Python", "Shell", "JavaScript", "C", "Lua"}
+	// demoSecondary lists languages that typically appear alongside a primary one.
+	demoSecondary = map[string][]string{
+		"Go":         {"Shell", "Makefile", "Dockerfile"},
+		"TypeScript": {"JavaScript", "CSS", "HTML"},
+		"Rust":       {"Shell", "C"},
+		"Python":     {"Shell", "Jupyter Notebook", "Dockerfile"},
+		"JavaScript": {"HTML", "CSS"},
+		"C":          {"Makefile", "C++", "Assembly"},
+	}
+	demoFirstNames = []string{"Ada", "Linus", "Grace", "Ken", "Margaret", "Dennis", "Barbara", "Rob", "Frances", "Guido", "Radia", "Bjarne"}
+	demoLastNames  = []string{"Okafor", "Lindqvist", "Moreau", "Tanaka", "Novak", "Haddad", "Fischer", "Costa", "Ivanova", "Byrne", "Sato", "Kowalski"}
+	demoCompanies  = []string{"@tidewater-labs", "Northwind", "@opencollective", "Fernhill Systems", "Blue Heron Data", ""}
+	demoLocations  = []string{"Lisbon, Portugal", "Toronto, Canada", "Berlin", "Remote", "Osaka, Japan", ""}
+	demoRoles      = []string{"Backend engineer", "Infrastructure tinkerer", "Open source maintainer", "Compiler nerd", "Frontend developer", "SRE"}
+	demoInterests  = []string{"distributed systems", "developer tooling", "terminal UIs", "databases", "accessibility", "observability", "type systems"}
+	demoNameParts  = [][]string{
+		{"tide", "ember", "lumen", "ferro", "quill", "orbit", "moss", "cinder", "harbor", "pixel", "vapor", "drift"},
+		{"kit", "db", "ui", "trace", "sync", "lint", "queue", "proxy", "cli", "fs", "cache", "graph"},
+	}
+	demoTopics       = []string{"cli", "tui", "database", "http", "parser", "testing", "observability", "devtools", "wasm", "api", "performance", "security"}
+	demoDescriptions = []string{
+		"A tiny, fast %s for people who like their tools boring",
+		"Experimental %s written over a few long weekends",
+		"Batteries-included %s with sensible defaults",
+		"Zero-dependency %s you can read in an afternoon",
+		"Yet another %s, but this one is mine",
+	}
+	demoNouns = map[string]string{"kit": "toolkit", "db": "embedded database", "ui": "component library", "trace": "tracing library", "sync": "file sync daemon",
+		"lint": "linter", "queue": "job queue", "proxy": "reverse proxy", "cli": "command-line tool", "fs": "virtual filesystem", "cache": "caching layer", "graph": "graph library"}
+	demoLicenses = []License{
+		{Key: "mit", Name: "MIT License", SPDXID: "MIT"},
+		{Key: "apache-2.0", Name: "Apache License 2.0", SPDXID: "Apache-2.0"},
+		{Key: "bsd-3-clause", Name: `BSD 3-Clause "New" or "Revised" License`, SPDXID: "BSD-3-Clause"},
+		{Key: "gpl-3.0", Name: "GNU General Public License v3.0", SPDXID: "GPL-3.0"},
+	}
+	demoUpstreams = []string{"golang/go", "rust-lang/rust", "neovim/neovim", "charmbracelet/bubbletea", "sqlite/sqlite", "python/cpython"}
+	demoStarred   = []string{"BurntSushi/ripgrep", "charmbracelet/lipgloss", "junegunn/fzf", "sharkdp/bat", "tailwindlabs/tailwindcss", "astral-sh/ruff", "tokio-rs/tokio", "jqlang/jq"}
+)
+
+// demoRand wraps the seeded source with the helpers the generator needs.
+type demoRand struct{ *rand.Rand }
+
+func (r demoRand) pick(s []string) string { return s[r.IntN(len(s))] }
+
+// weighted picks from s favouring earlier entries.
+func (r demoRand) weighted(s []string) string {
+	return s[int(float64(len(s))*math.Pow(r.Float64(), 2))]
+}
+
+// between returns a time uniformly between from and to.
+func (r demoRand) between(from, to time.Time) time.Time {
+	if !to.After(from) {
+		return from
+	}
+	return from.Add(time.Duration(r.Int64N(int64(to.Sub(from)))))
+}
+
+// DemoProfile generates a deterministic, plausible profile with repos from cfg,
+// filling every section so all output formats can be exercised offline.
 func DemoProfile(cfg DemoProfileConfig) (*Profile, []Repo) {
 	uname := cfg.Username
 	if uname == "" {
 		uname = "demo"
 	}
+	seed := cfg.seed()
+	r := demoRand{rand.New(rand.NewPCG(seed, seed>>1^0x9e3779b97f4a7c15))}
+	nRepos := cfg.Repos
+	if nRepos <= 0 {
+		nRepos = 8
+	}
+	langs := cfg.Languages
+	if len(langs) == 0 {
+		langs = demoLanguages
+	}
+	maxStars := cfg.MaxStars
+	if maxStars <= 0 {
+		maxStars = 800
+	}
+	activity := cfg.Activity
+	if activity == 0 {
+		activity = 12
+	}
+	now := cfg.ReferenceTime().UTC().Truncate(time.Second)
+	joined := now.AddDate(-3-r.IntN(9), -r.IntN(12), -r.IntN(28))
+
+	first, last := r.pick(demoFirstNames), r.pick(demoLastNames)
 	p := &Profile{
-		FullName:          uname,
-		URL:               fmt.Sprintf("https://github.com/%s", uname),
-		Bio:               `This is demo data used when the GitHub API is unavailable.\n\nNote: The GitHub API is unavailable in this environment.`,
-		FollowersAmount:   42,
-		FollowingAmount:   7,
-		PublicReposAmount: 5,
-		PublicGistsAmount: 1,
-	}
-	repos := []Repo{
-		{FullName: fmt.Sprintf("%s/repo-one", uname), HTMLURL: fmt.Sprintf("https://github.com/%s/repo-one", uname), StargazersCount: 10, ForksCount: 2, Language: "Go"},
-		{FullName: fmt.Sprintf("%s/repo-two", uname), HTMLURL: fmt.Sprintf("https://github.com/%s/repo-two", uname), StargazersCount: 5, ForksCount: 1, Language: "TypeScript"},
-		{FullName: fmt.Sprintf("%s/repo-three", uname), HTMLURL: fmt.Sprintf("https://github.com/%s/repo-three", uname), StargazersCount: 2, ForksCount: 0, Language: "Rust"},
-	}
-	totalStars := 0
-	totalForks := 0
-	for _, r := range repos {
-		totalStars += r.StargazersCount
-		totalForks += r.ForksCount
-	}
-	p.TotalStars = new(int)
-	*p.TotalStars = totalStars
-	p.TotalForks = new(int)
-	*p.TotalForks = totalForks
-	if len(repos) > 0 {
-		tmp := float32(totalStars) / float32(len(repos))
-		p.AvgStarsPerRepo = new(float32)
-		*p.AvgStarsPerRepo = tmp
+		Name:        uname,
+		AvatarURL:   fmt.Sprintf("https://avatars.githubusercontent.com/u/%d?v=4", 1000+r.IntN(9_000_000)),
+		URL:         "https://github.com/" + uname,
+		FullName:    first + " " + last,
+		Company:     r.pick(demoCompanies),
+		Blog:        fmt.Sprintf("https://%s.dev", uname),
+		Bio:         fmt.Sprintf("%s. Into %s and %s.\nDemo profile generated offline.", r.pick(demoRoles), r.pick(demoInterests), r.pick(demoInterests)),
+		Twitter:     uname,
+		MemberSince: joined.Format(time.RFC3339),
+		Hireable:    r.IntN(3) == 0,
+		Email:       fmt.Sprintf("%s@example.com", strings.ToLower(first)),
+		Location:    r.pick(demoLocations),
+	}
+
+	repos := make([]Repo, 0, nRepos)
+	used := map[string]bool{}
+	for i := 0; i < nRepos; i++ {
+		repos = append(repos, demoRepo(r, uname, i, maxStars, langs, joined, now, used))
+	}
+	p.PublicReposAmount = len(repos)
+
+	total := 0
+	for _, repo := range repos {
+		total += repo.StargazersCount
 	}
+	p.FollowersAmount = cfg.Followers
+	if p.FollowersAmount <= 0 {
+		p.FollowersAmount = total/3 + r.IntN(40)
+	}
+	p.FollowingAmount = r.IntN(120)
+
+	p.Gists = demoGists(r, uname, joined, now)
+	p.PublicGistsAmount = len(p.Gists)
+	p.Starred = demoStarredRepos(r, now)
+	if activity > 0 {
+		p.Events = demoEvents(r, repos, activity, now)
+		p.Contributions = demoCalendar(r, activity, now)
+	}
+	var pinned []Repo
+	for _, repo := range repos {
+		if !repo.Fork && len(pinned) < 4 {
+			pinned = append(pinned, repo)
+		}
+	}
+	p.Pinned = pinned
+
+	var gh Github
+	gh.calcRepoStats(p, repos)
 	return p, repos
 }
+
+// demoRepo generates the i-th repo. Stars follow a Zipf-like curve from maxStars.
+func demoRepo(r demoRand, owner string, i, maxStars int, langs []string, joined, now time.Time, used map[string]bool) Repo {
+	var name, suffix string
+	for {
+		prefix := r.pick(demoNameParts[0])
+		suffix = r.pick(demoNameParts[1])
+		name = prefix + "-" + suffix
+		if !used[name] {
+			used[name] = true
+			break
+		}
+		if len(used) >= len(demoNameParts[0])*len(demoNameParts[1]) {
+			name = fmt.Sprintf("%s-%d", name, i)
+			break
+		}
+	}
+	stars := int(float64(maxStars) / math.Pow(float64(i+1), 1.3) * (0.8 + 0.4*r.Float64()))
+	created := r.between(joined, now.AddDate(0, -1, 0))
+	pushed := r.between(created, now)
+	lang := r.weighted(langs)
+	repo := Repo{
+		ID:              100_000 + r.IntN(900_000_000),
+		Name:            name,
+		FullName:        owner + "/" + name,
+		HTMLURL:         "https://github.com/" + owner + "/" + name,
+		Description:     fmt.Sprintf(r.pick(demoDescriptions), demoNouns[suffix]),
+		StargazersCount: stars,
+		ForksCount:      stars / (5 + r.IntN(10)),
+		WatchersCount:   stars,
+		Language:        lang,
+		Size:            50 + r.IntN(20_000),
+		OpenIssuesCount: r.IntN(stars/20 + 2),
+		CreatedAt:       created.Format(time.RFC3339),
+		UpdatedAt:       pushed.Format(time.RFC3339),
+		PushedAt:        pushed.Format(time.RFC3339),
+		DefaultBranch:   "main",
+		Visibility:      "public",
+		Languages:       map[string]int{lang: 5_000 + r.IntN(400_000)},
+	}
+	for _, extra := range demoSecondary[lang] {
+		if r.IntN(2) == 0 {
+			repo.Languages[extra] = 200 + r.IntN(repo.Languages[lang]/4+1)
+		}
+	}
+	for _, t := range r.Perm(len(demoTopics))[:r.IntN(4)] {
+		repo.Topics = append(repo.Topics, demoTopics[t])
+	}
+	sort.Strings(repo.Topics)
+	if r.IntN(5) > 0 {
+		l := demoLicenses[r.IntN(len(demoLicenses))]
+		repo.License = &l
+	}
+	switch r.IntN(12) {
+	case 0:
+		// Forks are the least starred and point at a well-known upstream.
+		upstream := r.pick(demoUpstreams)
+		repo.Fork = true
+		repo.StargazersCount, repo.WatchersCount, repo.ForksCount = r.IntN(3), 0, 0
+		repo.Parent = &Repo{FullName: upstream, HTMLURL: "https://github.com/" + upstream}
+	case 1:
+		repo.Archived = true
+	case 2:
+		repo.IsTemplate = true
+	}
+	if i == 0 || r.IntN(6) == 0 {
+		repo.Homepage = fmt.Sprintf("https://%s.%s.dev", name, owner)
+		repo.HasPages = true
+	}
+	return repo
+}
+
+func demoGists(r demoRand, owner string, joined, now time.Time) []Gist {
+	kinds := []struct {
+		desc  string
+		files []GistFile
+	}{
+		{"dotfiles snippets", []GistFile{{Filename: ".zshrc", Language: "Shell"}, {Filename: "init.lua", Language: "Lua"}}},
+		{"Handy git aliases", []GistFile{{Filename: "gitconfig", Language: "INI"}}},
+		{"Benchmark: channels vs mutex", []GistFile{{Filename: "bench_test.go", Language: "Go"}, {Filename: "results.md", Language: "Markdown"}}},
+		{"Postgres query cheatsheet", []GistFile{{Filename: "cheatsheet.sql", Language: "SQL"}}},
+	}
+	n := 1 + r.IntN(len(kinds))
+	gists := make([]Gist, 0, n)
+	for _, k := range r.Perm(len(kinds))[:n] {
+		kind := kinds[k]
+		id := fmt.Sprintf("%032x", r.Uint64())
+		created := r.between(joined, now)
+		files := map[string]GistFile{}
+		for _, f := range kind.files {
+			f.Size = 200 + r.IntN(4000)
+			files[f.Filename] = f
+		}
+		gists = append(gists, Gist{
+			ID:          id,
+			HTMLURL:     fmt.Sprintf("https://gist.github.com/%s/%s", owner, id),
+			Description: kind.desc,
+			Public:      true,
+			Files:       files,
+			Comments:    r.IntN(8),
+			CreatedAt:   created.Format(time.RFC3339),
+			UpdatedAt:   r.between(created, now).Format(time.RFC3339),
+		})
+	}
+	sort.Slice(gists, func(i, j int) bool { return gists[i].UpdatedAt > gists[j].UpdatedAt })
+	return gists
+}
+
+func demoStarredRepos(r demoRand, now time.Time) []StarredRepo {
+	var starred []StarredRepo
+	at := now
+	for _, i := range r.Perm(len(demoStarred))[:3+r.IntN(len(demoStarred)-3)] {
+		full := demoStarred[i]
+		at = at.Add(-time.Duration(1+r.IntN(20*24)) * time.Hour)
+		stars := 5_000 + r.IntN(60_000)
+		starred = append(starred, StarredRepo{
+			StarredAt: at.Format(time.RFC3339),
+			Repo: Repo{
+				FullName:        full,
+				Name:            full[strings.Index(full, "/")+1:],
+				HTMLURL:         "https://github.com/" + full,
+				StargazersCount: stars,
+				Language:        r.pick(demoLanguages),
+				Topics:          []string{r.pick(demoTopics), r.pick(demoTopics)},
+			},
+		})
+	}
+	return starred
+}
+
+// demoEvents generates about perWeek events a week over the activity window,
+// newest first, as the events API returns them.
+func demoEvents(r demoRand, repos []Repo, perWeek int, now time.Time) []Event {
+	n := perWeek * 30 / 7
+	events := make([]Event, 0, n)
+	for i := 0; i < n; i++ {
+		repo := repos[int(float64(len(repos))*math.Pow(r.Float64(), 2))]
+		at := now.Add(-time.Duration(r.Int64N(int64(ActivityWindow))))
+		var kind string
+		var payload any
+		switch roll := r.IntN(20); {
+		case roll < 12:
+			kind = PushEvent
+			payload = PushPayload{Ref: "refs/heads/main", Size: 1 + r.IntN(6)}
+		case roll < 15:
+			kind = PullRequestEvent
+			pr := PullRequestPayload{Action: "opened", Number: 1 + r.IntN(400)}
+			if r.IntN(2) == 0 {
+				pr.Action = "closed"
+				pr.PullRequest.Merged = true
+			}
+			payload = pr
+		case roll < 17:
+			kind = IssuesEvent
+			payload = IssuesPayload{Action: "opened"}
+		case roll < 18:
+			kind = ReleaseEvent
+			rel := ReleasePayload{Action: "published"}
+			rel.Release.TagName = fmt.Sprintf("v%d.%d.%d", r.IntN(3), r.IntN(20), r.IntN(10))
+			payload = rel
+		case roll < 19:
+			kind = CreateEvent
+			payload = CreatePayload{RefType: "branch", Ref: "feature/" + r.pick(demoTopics)}
+		default:
+			kind = WatchEvent
+			payload = WatchPayload{Action: "started"}
+		}
+		raw, _ := json.Marshal(payload)
+		events = append(events, Event{
+			ID:        fmt.Sprintf("%d", 30_000_000_000+r.Int64N(1_000_000_000)),
+			Type:      kind,
+			Repo:      EventRepo{Name: repo.FullName, URL: "https://api.github.com/repos/" + repo.FullName},
+			CreatedAt: at.Format(time.RFC3339),
+			Payload:   raw,
+		})
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].CreatedAt > events[j].CreatedAt })
+	return events
+}
+
+// demoCalendar generates the last year of contributions, busier on weekdays,
+// with levels assigned by quartile of the non-zero days as GitHub does.
+func demoCalendar(r demoRand, perWeek int, now time.Time) *ContributionCalendar {
+	end := now.Truncate(24 * time.Hour)
+	start := end.AddDate(-1, 0, 0)
+	start = start.AddDate(0, 0, -int(start.Weekday()))
+	cal := &ContributionCalendar{}
+	var week ContributionWeek
+	var counts []int
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		rate := float64(perWeek) / 7
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			rate /= 3
+		}
+		count := 0
+		if r.Float64() < 0.7 {
+			count = int(rate * 2 * r.Float64() * r.Float64() * 2)
+		}
+		counts = append(counts, count)
+		week.ContributionDays = append(week.ContributionDays, ContributionDay{Date: d.Format("2006-01-02"), Weekday: int(d.Weekday()), ContributionCount: count})
+		cal.TotalContributions += count
+		if d.Weekday() == time.Saturday {
+			cal.Weeks = append(cal.Weeks, week)
+			week = ContributionWeek{}
+		}
+	}
+	if len(week.ContributionDays) > 0 {
+		cal.Weeks = append(cal.Weeks, week)
+	}
+
+	var nonZero []int
+	for _, c := range counts {
+		if c > 0 {
+			nonZero = append(nonZero, c)
+		}
+	}
+	sort.Ints(nonZero)
+	quartile := func(q int) int {
+		if len(nonZero) == 0 {
+			return 0
+		}
+		return nonZero[(len(nonZero)-1)*q/4]
+	}
+	for wi := range cal.Weeks {
+		for di := range cal.Weeks[wi].ContributionDays {
+			day := &cal.Weeks[wi].ContributionDays[di]
+			switch c := day.ContributionCount; {
+			case c == 0:
+				day.ContributionLevel = LevelNone
+			case c <= quartile(1):
+				day.ContributionLevel = LevelFirstQuartile
+			case c <= quartile(2):
+				day.ContributionLevel = LevelSecondQuartile
+			case c <= quartile(3):
+				day.ContributionLevel = LevelThirdQuartile
+			default:
+				day.ContributionLevel = LevelFourthQuartile
+			}
+		}
+	}
+	return cal
+}
@@ -0,0 +1,85 @@
+package github
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// GraphQLMaxAge is how long an ETagCache reuses the response to a query sent
+// with graphqlReused. GraphQL requests are POSTs without ETags, so they can't be
+// revalidated like REST GETs.
+const GraphQLMaxAge = 30 * time.Minute
+
+// ETagCache remembers GET response bodies by URL so repeated requests can be
+// sent with If-None-Match. GitHub answers unchanged resources with 304 Not
+// Modified, which doesn't count against the rate limit. It also holds the
+// responses of slow-changing GraphQL queries for GraphQLMaxAge.
+type ETagCache struct {
+	mu      sync.Mutex
+	entries map[string]etagEntry
+	queries map[string]queryEntry
+}
+
+type etagEntry struct {
+	etag string
+	body []byte
+}
+
+type queryEntry struct {
+	fetched time.Time
+	body    []byte
+}
+
+func NewETagCache() *ETagCache {
+	return &ETagCache{entries: map[string]etagEntry{}, queries: map[string]queryEntry{}}
+}
+
+// query returns the response stored for a GraphQL payload within GraphQLMaxAge.
+func (c *ETagCache) query(payload []byte) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.queries[string(payload)]
+	if !ok || time.Since(e.fetched) >= GraphQLMaxAge {
+		return nil, false
+	}
+	return e.body, true
+}
+
+// storeQuery remembers the response to a GraphQL payload.
+func (c *ETagCache) storeQuery(payload, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queries[string(payload)] = queryEntry{fetched: time.Now(), body: body}
+}
+
+// prepare adds If-None-Match to req when a response for its URL is cached.
+func (c *ETagCache) prepare(req *http.Request) {
+	if req.Method != http.MethodGet {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[req.URL.String()]; ok {
+		req.Header.Set("If-None-Match", e.etag)
+	}
+}
+
+// cached returns the body stored for req's URL after a 304 response.
+func (c *ETagCache) cached(req *http.Request) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[req.URL.String()]
+	return e.body, ok
+}
+
+// store remembers a 200 response that carries an ETag.
+func (c *ETagCache) store(req *http.Request, resp *http.Response, body []byte) {
+	etag := resp.Header.Get("ETag")
+	if req.Method != http.MethodGet || etag == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[req.URL.String()] = etagEntry{etag: etag, body: body}
+}
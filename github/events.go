@@ -0,0 +1,197 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Event kinds with a typed payload. Other kinds are kept but only counted.
+const (
+	PushEvent        = "PushEvent"
+	PullRequestEvent = "PullRequestEvent"
+	IssuesEvent      = "IssuesEvent"
+	CreateEvent      = "CreateEvent"
+	ReleaseEvent     = "ReleaseEvent"
+	WatchEvent       = "WatchEvent"
+)
+
+// ActivityWindow is the period the Activity section summarises.
+const ActivityWindow = 30 * 24 * time.Hour
+
+// maxEventPages matches the API, which serves at most 300 events (3 pages of 100).
+const maxEventPages = 3
+
+type Event struct {
+	ID        string          `json:"id,omitempty"`
+	Type      string          `json:"type,omitempty"`
+	Repo      EventRepo       `json:"repo"`
+	CreatedAt string          `json:"created_at,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+type EventRepo struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type PushPayload struct {
+	Ref          string `json:"ref,omitempty"`
+	Size         int    `json:"size,omitempty"`
+	DistinctSize int    `json:"distinct_size,omitempty"`
+	Commits      []struct {
+		SHA     string `json:"sha,omitempty"`
+		Message string `json:"message,omitempty"`
+	} `json:"commits,omitempty"`
+}
+
+// CommitCount returns the number of commits in the push. Newer payloads may omit
+// size, so the commit list is used as a fallback.
+func (p PushPayload) CommitCount() int {
+	if p.Size > 0 {
+		return p.Size
+	}
+	return len(p.Commits)
+}
+
+type PullRequestPayload struct {
+	Action      string `json:"action,omitempty"`
+	Number      int    `json:"number,omitempty"`
+	PullRequest struct {
+		Title   string `json:"title,omitempty"`
+		HTMLURL string `json:"html_url,omitempty"`
+		Merged  bool   `json:"merged,omitempty"`
+	} `json:"pull_request"`
+}
+
+type IssuesPayload struct {
+	Action string `json:"action,omitempty"`
+	Issue  struct {
+		Number  int    `json:"number,omitempty"`
+		Title   string `json:"title,omitempty"`
+		HTMLURL string `json:"html_url,omitempty"`
+	} `json:"issue"`
+}
+
+type CreatePayload struct {
+	RefType     string `json:"ref_type,omitempty"`
+	Ref         string `json:"ref,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type ReleasePayload struct {
+	Action  string `json:"action,omitempty"`
+	Release struct {
+		TagName string `json:"tag_name,omitempty"`
+		Name    string `json:"name,omitempty"`
+		HTMLURL string `json:"html_url,omitempty"`
+	} `json:"release"`
+}
+
+type WatchPayload struct {
+	Action string `json:"action,omitempty"`
+}
+
+// ParsePayload decodes the payload into the typed struct for the event kind, e.g.
+// *PushPayload for a PushEvent. Kinds without a typed model return nil, nil.
+func (e Event) ParsePayload() (any, error) {
+	var v any
+	switch e.Type {
+	case PushEvent:
+		v = &PushPayload{}
+	case PullRequestEvent:
+		v = &PullRequestPayload{}
+	case IssuesEvent:
+		v = &IssuesPayload{}
+	case CreateEvent:
+		v = &CreatePayload{}
+	case ReleaseEvent:
+		v = &ReleasePayload{}
+	case WatchEvent:
+		v = &WatchPayload{}
+	default:
+		return nil, nil
+	}
+	if len(e.Payload) == 0 {
+		return v, nil
+	}
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return nil, fmt.Errorf("unmarshal %s payload: %w", e.Type, err)
+	}
+	return v, nil
+}
+
+// ActivitySummary totals a user's public events since a point in time.
+type ActivitySummary struct {
+	Since         time.Time
+	Events        int
+	Pushes        int
+	Commits       int
+	PRsOpened     int
+	PRsMerged     int
+	IssuesOpened  int
+	ReposCreated  int
+	Releases      int
+	StarsGiven    int
+	EventsPerRepo map[string]int
+}
+
+// SummarizeActivity counts the events created at or after since. Events with
+// an unparseable timestamp or payload are skipped.
+func SummarizeActivity(events []Event, since time.Time) ActivitySummary {
+	sum := ActivitySummary{Since: since, EventsPerRepo: map[string]int{}}
+	for _, e := range events {
+		at, err := time.Parse(time.RFC3339, e.CreatedAt)
+		if err != nil || at.Before(since) {
+			continue
+		}
+		payload, err := e.ParsePayload()
+		if err != nil {
+			continue
+		}
+		sum.Events++
+		if e.Repo.Name != "" {
+			sum.EventsPerRepo[e.Repo.Name]++
+		}
+		switch pl := payload.(type) {
+		case *PushPayload:
+			sum.Pushes++
+			sum.Commits += pl.CommitCount()
+		case *PullRequestPayload:
+			switch {
+			case pl.Action == "opened":
+				sum.PRsOpened++
+			case pl.Action == "closed" && pl.PullRequest.Merged:
+				sum.PRsMerged++
+			}
+		case *IssuesPayload:
+			if pl.Action == "opened" {
+				sum.IssuesOpened++
+			}
+		case *CreatePayload:
+			if pl.RefType == "repository" {
+				sum.ReposCreated++
+			}
+		case *ReleasePayload:
+			if pl.Action == "published" {
+				sum.Releases++
+			}
+		case *WatchPayload:
+			sum.StarsGiven++
+		}
+	}
+	return sum
+}
+
+// GetEvents returns the user's recent public events, newest first. The API keeps
+// at most 300 events from the last 90 days.
+func (gh *Github) GetEvents(ctx context.Context, username string) ([]Event, error) {
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+	u := fmt.Sprintf("https://api.github.com/users/%s/events/public", url.PathEscape(username))
+	return paginate[Event](ctx, gh, u, maxEventPages)
+}
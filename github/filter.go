@@ -0,0 +1,99 @@
+package github
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RepoFilter selects which repos are listed. The zero value keeps every repo.
+type RepoFilter struct {
+	NoForks     bool
+	NoArchived  bool
+	NoTemplates bool
+	NoMirrors   bool
+	// Language and Topic match case-insensitively; empty matches all.
+	Language string
+	Topic    string
+	// UpdatedSince drops repos last pushed (or updated, if the push time is
+	// unknown) before it; the zero time disables the check.
+	UpdatedSince time.Time
+}
+
+// Match reports whether r passes every condition of the filter.
+func (f RepoFilter) Match(r Repo) bool {
+	switch {
+	case f.NoForks && r.Fork,
+		f.NoArchived && r.Archived,
+		f.NoTemplates && r.IsTemplate,
+		f.NoMirrors && r.MirrorURL != "":
+		return false
+	}
+	if f.Language != "" && !strings.EqualFold(f.Language, r.Language) {
+		return false
+	}
+	if f.Topic != "" {
+		found := false
+		for _, t := range r.Topics {
+			if strings.EqualFold(f.Topic, t) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !f.UpdatedSince.IsZero() {
+		ts := r.PushedAt
+		if ts == "" {
+			ts = r.UpdatedAt
+		}
+		at, err := time.Parse(time.RFC3339, ts)
+		if err != nil || at.Before(f.UpdatedSince) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterRepos returns the repos matching f, in their original order.
+func FilterRepos(repos []Repo, f RepoFilter) []Repo {
+	out := make([]Repo, 0, len(repos))
+	for _, r := range repos {
+		if f.Match(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// ParseSince turns a point in time given on the command line into a time. It
+// accepts a date (2006-01-02), an RFC 3339 timestamp, an age in days, weeks or
+// years ("30d", "2w", "1y") or a Go duration ("36h"), ages counting back from now.
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if n := len(s); n > 1 {
+		if v, err := strconv.Atoi(s[:n-1]); err == nil && v >= 0 {
+			switch s[n-1] {
+			case 'd':
+				return now.AddDate(0, 0, -v), nil
+			case 'w':
+				return now.AddDate(0, 0, -7*v), nil
+			case 'y':
+				return now.AddDate(-v, 0, 0), nil
+			}
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (want a date like 2024-01-31 or an age like 30d, 2w, 1y)", s)
+}
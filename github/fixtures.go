@@ -0,0 +1,192 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Fixture is one recorded API exchange, stored as a JSON file.
+type Fixture struct {
+	Method      string            `json:"method"`
+	URL         string            `json:"url"`
+	RequestBody string            `json:"request_body,omitempty"`
+	Status      int               `json:"status"`
+	Header      map[string]string `json:"header,omitempty"`
+	// Body is the response body as text, or base64 when Encoding is "base64",
+	// which is used for bodies that aren't UTF-8 such as images.
+	Body     string `json:"body"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// fixtureHeaders are the response headers kept in fixtures; the rest, including
+// cookies, are dropped.
+var fixtureHeaders = []string{"Content-Type", "ETag", "Link", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"}
+
+// tokenPattern matches GitHub token formats so they can be scrubbed from URLs and bodies.
+var tokenPattern = regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`)
+
+func scrubTokens(s string) string {
+	return tokenPattern.ReplaceAllString(s, "REDACTED")
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// fixtureName derives a stable file name from a request: a readable prefix
+// from the method and path plus a hash of the method, URL and body.
+func fixtureName(method, rawURL, body string) string {
+	sum := sha256.Sum256([]byte(method + " " + rawURL + "\n" + body))
+	readable := rawURL
+	if i := strings.Index(readable, "://"); i >= 0 {
+		readable = readable[i+3:]
+	}
+	if i := strings.Index(readable, "/"); i >= 0 {
+		readable = readable[i+1:]
+	}
+	readable = strings.Trim(unsafeNameChars.ReplaceAllString(readable, "_"), "_")
+	if len(readable) > 80 {
+		readable = readable[:80]
+	}
+	return fmt.Sprintf("%s_%s_%s.json", method, readable, hex.EncodeToString(sum[:])[:12])
+}
+
+// readRequestBody consumes req's body and returns it with a clone of req that
+// can still be sent, as RoundTrip must not modify the request it is given.
+func readRequestBody(req *http.Request) (string, *http.Request, error) {
+	if req.Body == nil {
+		return "", req, nil
+	}
+	b, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(b))
+	return string(b), clone, nil
+}
+
+// RecordingTransport sends requests with Base and saves every exchange to Dir as
+// a Fixture. Request headers, and so the Authorization token, are never saved,
+// and token-like strings are scrubbed from URLs and bodies.
+type RecordingTransport struct {
+	Base http.RoundTripper
+	Dir  string
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	reqBody, send, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := base.RoundTrip(send)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	u := scrubTokens(req.URL.String())
+	f := Fixture{Method: req.Method, URL: u, RequestBody: scrubTokens(reqBody), Status: resp.StatusCode}
+	// JSON strings can't hold arbitrary bytes, and scrubbing would corrupt them.
+	if utf8.Valid(body) {
+		f.Body = scrubTokens(string(body))
+	} else {
+		f.Body, f.Encoding = base64.StdEncoding.EncodeToString(body), "base64"
+	}
+	for _, h := range fixtureHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			if f.Header == nil {
+				f.Header = map[string]string{}
+			}
+			f.Header[h] = v
+		}
+	}
+	if err := os.MkdirAll(t.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(t.Dir, fixtureName(f.Method, f.URL, f.RequestBody)), b.Bytes(), 0o644); err != nil {
+		return nil, fmt.Errorf("record fixture: %w", err)
+	}
+	return resp, nil
+}
+
+// ErrNoFixture is returned by ReplayTransport for requests that weren't recorded.
+var ErrNoFixture = errors.New("no recorded fixture")
+
+// ReplayTransport answers requests from fixtures saved by RecordingTransport,
+// without touching the network.
+type ReplayTransport struct {
+	Dir string
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, _, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	u := scrubTokens(req.URL.String())
+	name := fixtureName(req.Method, u, scrubTokens(reqBody))
+	b, err := os.ReadFile(filepath.Join(t.Dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s %s (%s)", ErrNoFixture, req.Method, u, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f Fixture
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	body := []byte(f.Body)
+	switch f.Encoding {
+	case "":
+	case "base64":
+		if body, err = base64.StdEncoding.DecodeString(f.Body); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unknown body encoding %q", name, f.Encoding)
+	}
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+	for k, v := range f.Header {
+		resp.Header.Set(k, v)
+	}
+	return resp, nil
+}
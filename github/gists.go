@@ -0,0 +1,93 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+type Gist struct {
+	ID          string              `json:"id,omitempty"`
+	HTMLURL     string              `json:"html_url,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Public      bool                `json:"public,omitempty"`
+	Files       map[string]GistFile `json:"files,omitempty"`
+	Comments    int                 `json:"comments,omitempty"`
+	CreatedAt   string              `json:"created_at,omitempty"`
+	UpdatedAt   string              `json:"updated_at,omitempty"`
+}
+
+type GistFile struct {
+	Filename string `json:"filename,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Language string `json:"language,omitempty"`
+	RawURL   string `json:"raw_url,omitempty"`
+	Size     int    `json:"size,omitempty"`
+}
+
+// FileCount returns how many files the gist contains.
+func (g Gist) FileCount() int {
+	return len(g.Files)
+}
+
+// Languages returns the distinct languages of the gist's files, sorted by name.
+// Files GitHub could not classify are skipped.
+func (g Gist) Languages() []string {
+	seen := map[string]bool{}
+	var langs []string
+	for _, f := range g.Files {
+		if f.Language == "" || seen[f.Language] {
+			continue
+		}
+		seen[f.Language] = true
+		langs = append(langs, f.Language)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// GistStats holds totals across a user's gists.
+type GistStats struct {
+	Gists     int
+	Files     int
+	Comments  int
+	Languages map[string]int
+}
+
+// CalcGistStats totals files and comments and counts, per language, how many gists use it.
+func CalcGistStats(gists []Gist) GistStats {
+	st := GistStats{Gists: len(gists), Languages: map[string]int{}}
+	for _, g := range gists {
+		st.Files += g.FileCount()
+		st.Comments += g.Comments
+		for _, l := range g.Languages() {
+			st.Languages[l]++
+		}
+	}
+	return st
+}
+
+// MaxGists is how many gists GetGists fetches. The profile's public_gists
+// already counts them all, so one page of the latest is enough.
+const MaxGists = 5
+
+// GetGists returns the user's MaxGists most recently updated public gists.
+func (gh *Github) GetGists(ctx context.Context, username string) ([]Gist, error) {
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+	u := fmt.Sprintf("https://api.github.com/users/%s/gists?per_page=%d", url.PathEscape(username), MaxGists)
+	body, err := gh.doRequest(ctx, http.MethodGet, u)
+	if err != nil {
+		return nil, err
+	}
+	var gists []Gist
+	if err := json.Unmarshal(body, &gists); err != nil {
+		return nil, fmt.Errorf("unmarshal gists: %w", err)
+	}
+	return gists, nil
+}
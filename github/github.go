@@ -2,39 +2,64 @@ package github
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
 )
 
 type Github struct {
 	Client *http.Client
+	// Token is an optional personal access token. It raises the REST rate limit
+	// and is required for the GraphQL API.
+	Token string
+	// ETags, when set, turns repeated GETs into conditional requests and reuses
+	// slow-changing GraphQL responses for GraphQLMaxAge.
+	ETags *ETagCache
+
+	rateMu sync.Mutex
+	rate   RateLimit
 }
 
 type Profile struct {
-	Name              string    `json:"name,omitempty"`
-	AvatarURL         string    `json:"avatar_url,omitempty"`
-	URL               string    `json:"url,omitempty"`
-	FullName          string    `json:"full_name,omitempty"`
-	Company           string    `json:"company,omitempty"`
-	Blog              string    `json:"blog,omitempty"`
-	Bio               string    `json:"bio,omitempty"`
-	Twitter           string    `json:"twitter,omitempty"`
-	FollowersAmount   int       `json:"followers_amount,omitempty"`
-	FollowingAmount   int       `json:"following_amount,omitempty"`
-	MemberSince       string    `json:"member_since,omitempty"`
-	Hireable          bool      `json:"hireable,omitempty"`
-	Email             string    `json:"email,omitempty"`
-	PublicReposAmount int       `json:"public_repos_amount,omitempty"`
-	PublicGistsAmount int       `json:"public_gists_amount,omitempty"`
-	TotalStars        *int      `json:"total_stars,omitempty"`
-	TotalForks        *int      `json:"total_forks,omitempty"`
-	AvgStarsPerRepo   *float32  `json:"avg_stars_per_repo,omitempty"`
-	Repos             []Repo    `json:"repos,omitempty"`
-	Followers         []Profile `json:"followers,omitempty"`
+	Name                string                `json:"name,omitempty"`
+	AvatarURL           string                `json:"avatar_url,omitempty"`
+	URL                 string                `json:"url,omitempty"`
+	FullName            string                `json:"full_name,omitempty"`
+	Company             string                `json:"company,omitempty"`
+	Location            string                `json:"location,omitempty"`
+	Blog                string                `json:"blog,omitempty"`
+	Bio                 string                `json:"bio,omitempty"`
+	Twitter             string                `json:"twitter,omitempty"`
+	FollowersAmount     int                   `json:"followers_amount,omitempty"`
+	FollowingAmount     int                   `json:"following_amount,omitempty"`
+	MemberSince         string                `json:"member_since,omitempty"`
+	Hireable            bool                  `json:"hireable,omitempty"`
+	Email               string                `json:"email,omitempty"`
+	PublicReposAmount   int                   `json:"public_repos_amount,omitempty"`
+	PublicGistsAmount   int                   `json:"public_gists_amount,omitempty"`
+	PrivateReposAmount  int                   `json:"private_repos_amount,omitempty"`
+	IsAuthenticatedUser bool                  `json:"is_authenticated_user,omitempty"`
+	TotalStars          *int                  `json:"total_stars,omitempty"`
+	TotalForks          *int                  `json:"total_forks,omitempty"`
+	PrivateStars        *int                  `json:"private_stars,omitempty"`
+	PrivateForks        *int                  `json:"private_forks,omitempty"`
+	AvgStarsPerRepo     *float32              `json:"avg_stars_per_repo,omitempty"`
+	Repos               []Repo                `json:"repos,omitempty"`
+	Followers           []Profile             `json:"followers,omitempty"`
+	Gists               []Gist                `json:"gists,omitempty"`
+	Starred             []StarredRepo         `json:"starred,omitempty"`
+	Events              []Event               `json:"events,omitempty"`
+	Contributions       *ContributionCalendar `json:"contributions,omitempty"`
+	Pinned              []Repo                `json:"pinned,omitempty"`
 }
 
 type Repo struct {
@@ -46,36 +71,111 @@ type Repo struct {
 	StargazersCount int    `json:"stargazers_count,omitempty"`
 	ForksCount      int    `json:"forks_count,omitempty"`
 	WatchersCount   int    `json:"watchers_count,omitempty"`
-	Language        string `json:"language,omitempty"`
-	Size            int    `json:"size,omitempty"`
-	OpenIssuesCount int    `json:"open_issues_count,omitempty"`
-	CreatedAt       string `json:"created_at,omitempty"`
-	UpdatedAt       string `json:"updated_at,omitempty"`
-	Fork            bool   `json:"fork,omitempty"`
-	DefaultBranch   string `json:"default_branch,omitempty"`
+	// SubscribersCount is the real watcher count; WatchersCount mirrors the
+	// stars. Only GetRepo returns it.
+	SubscribersCount int      `json:"subscribers_count,omitempty"`
+	Language         string   `json:"language,omitempty"`
+	Size             int      `json:"size,omitempty"`
+	OpenIssuesCount  int      `json:"open_issues_count,omitempty"`
+	CreatedAt        string   `json:"created_at,omitempty"`
+	UpdatedAt        string   `json:"updated_at,omitempty"`
+	PushedAt         string   `json:"pushed_at,omitempty"`
+	Fork             bool     `json:"fork,omitempty"`
+	Private          bool     `json:"private,omitempty"`
+	Archived         bool     `json:"archived,omitempty"`
+	IsTemplate       bool     `json:"is_template,omitempty"`
+	MirrorURL        string   `json:"mirror_url,omitempty"`
+	DefaultBranch    string   `json:"default_branch,omitempty"`
+	Topics           []string `json:"topics,omitempty"`
+	License          *License `json:"license,omitempty"`
+	Visibility       string   `json:"visibility,omitempty"`
+	Homepage         string   `json:"homepage,omitempty"`
+	HasPages         bool     `json:"has_pages,omitempty"`
+	// Parent is the repo a fork was forked from. The REST repo lists leave it
+	// out; FillParents looks it up.
+	Parent    *Repo          `json:"parent,omitempty"`
+	Languages map[string]int `json:"languages,omitempty"`
+}
+
+// JoinedAt parses MemberSince. ok is false when it is missing or malformed.
+func (p *Profile) JoinedAt() (t time.Time, ok bool) {
+	t, err := time.Parse(time.RFC3339, p.MemberSince)
+	return t, err == nil
+}
+
+// License is a repository license as detected by GitHub.
+type License struct {
+	Key    string `json:"key,omitempty"`
+	Name   string `json:"name,omitempty"`
+	SPDXID string `json:"spdx_id,omitempty"`
 }
 
 func (gh *Github) doRequest(ctx context.Context, method, urlStr string) ([]byte, error) {
-	client := gh.Client
-	if client == nil {
-		client = http.DefaultClient
-	}
+	return gh.doRequestAccept(ctx, method, urlStr, "")
+}
+
+// doRequestAccept is doRequest with a custom Accept media type, used by endpoints
+// that only return extra fields (e.g. starred_at) for a specific media type.
+func (gh *Github) doRequestAccept(ctx context.Context, method, urlStr, accept string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, method, urlStr, nil)
 	if err != nil {
 		return nil, fmt.Errorf("request: %w", err)
 	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	return gh.do(req)
+}
+
+// StatusError is returned for responses other than 200 OK, such as 404 for an
+// unknown user.
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("github: %s %s returned %d: %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+// do sends req with the client headers and returns the body of a 200 response.
+func (gh *Github) do(req *http.Request) ([]byte, error) {
+	client := gh.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
 	req.Header.Set("User-Agent", "ghprofile-client")
+	if gh.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+gh.Token)
+	}
+	if gh.ETags != nil {
+		gh.ETags.prepare(req)
+	}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("do: %w", err)
 	}
 	defer resp.Body.Close()
+	gh.recordRateLimit(resp.Header)
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("read body: %w", err)
 	}
+	if rlErr := rateLimitError(resp); rlErr != nil {
+		return nil, rlErr
+	}
+	if resp.StatusCode == http.StatusNotModified && gh.ETags != nil {
+		if cached, ok := gh.ETags.cached(req); ok {
+			return cached, nil
+		}
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("github: %s %s returned %d: %s", method, urlStr, resp.StatusCode, string(body))
+		return nil, &StatusError{Method: req.Method, URL: req.URL.String(), StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	if gh.ETags != nil {
+		gh.ETags.store(req, resp, body)
 	}
 	return body, nil
 }
@@ -89,22 +189,71 @@ func (gh *Github) GetProfile(ctx context.Context, username string) (*Profile, er
 	if err != nil {
 		return nil, err
 	}
+	return decodeProfile(body, false)
+}
+
+// GetAuthenticatedProfile returns the profile of the token owner, including the
+// private repo count. It needs a token.
+func (gh *Github) GetAuthenticatedProfile(ctx context.Context) (*Profile, error) {
+	if gh.Token == "" {
+		return nil, errors.New("github: a token is required to fetch the authenticated user")
+	}
+	body, err := gh.doRequest(ctx, http.MethodGet, "https://api.github.com/user")
+	if err != nil {
+		return nil, err
+	}
+	return decodeProfile(body, true)
+}
+
+// TokenOwner returns the login of the token owner. It is looked up from /user
+// once per token and remembered in the cache directory, under a hash of the
+// token, so later runs can tell whether a user owns the token without a request.
+func (gh *Github) TokenOwner(ctx context.Context) (string, error) {
+	if gh.Token == "" {
+		return "", errors.New("github: a token is required to look up its owner")
+	}
+	sum := sha256.Sum256([]byte(gh.Token))
+	path := ""
+	if dir, err := cacheDir("owners"); err == nil {
+		path = dir + "/" + hex.EncodeToString(sum[:8])
+		if b, err := os.ReadFile(path); err == nil {
+			if login := strings.TrimSpace(string(b)); login != "" {
+				return login, nil
+			}
+		}
+	}
+	p, err := gh.GetAuthenticatedProfile(ctx)
+	if err != nil {
+		return "", err
+	}
+	if path != "" {
+		os.WriteFile(path, []byte(p.Name+"\n"), 0o600)
+	}
+	return p.Name, nil
+}
+
+// decodeProfile converts a /users/{name} or /user response. /users/{name} also
+// returns the private fields when the token belongs to that user, so they are
+// only kept when own is set, keeping GetProfile public-only.
+func decodeProfile(body []byte, own bool) (*Profile, error) {
 	var g struct {
-		Login       string `json:"login"`
-		AvatarURL   string `json:"avatar_url"`
-		HTMLURL     string `json:"html_url"`
-		Name        string `json:"name"`
-		Company     string `json:"company"`
-		Blog        string `json:"blog"`
-		Bio         string `json:"bio"`
-		Twitter     string `json:"twitter_username"`
-		Followers   int    `json:"followers"`
-		Following   int    `json:"following"`
-		CreatedAt   string `json:"created_at"`
-		Hireable    bool   `json:"hireable"`
-		Email       string `json:"email"`
-		PublicRepos int    `json:"public_repos"`
-		PublicGists int    `json:"public_gists"`
+		Login        string `json:"login"`
+		AvatarURL    string `json:"avatar_url"`
+		HTMLURL      string `json:"html_url"`
+		Name         string `json:"name"`
+		Company      string `json:"company"`
+		Location     string `json:"location"`
+		Blog         string `json:"blog"`
+		Bio          string `json:"bio"`
+		Twitter      string `json:"twitter_username"`
+		Followers    int    `json:"followers"`
+		Following    int    `json:"following"`
+		CreatedAt    string `json:"created_at"`
+		Hireable     bool   `json:"hireable"`
+		Email        string `json:"email"`
+		PublicRepos  int    `json:"public_repos"`
+		PublicGists  int    `json:"public_gists"`
+		PrivateRepos *int   `json:"owned_private_repos"`
 	}
 	if err := json.Unmarshal(body, &g); err != nil {
 		return nil, fmt.Errorf("unmarshal profile: %w", err)
@@ -115,6 +264,7 @@ func (gh *Github) GetProfile(ctx context.Context, username string) (*Profile, er
 		URL:               g.HTMLURL,
 		FullName:          g.Name,
 		Company:           g.Company,
+		Location:          g.Location,
 		Blog:              g.Blog,
 		Bio:               g.Bio,
 		Twitter:           g.Twitter,
@@ -126,32 +276,46 @@ func (gh *Github) GetProfile(ctx context.Context, username string) (*Profile, er
 		PublicReposAmount: g.PublicRepos,
 		PublicGistsAmount: g.PublicGists,
 	}
+	if own && g.PrivateRepos != nil {
+		p.IsAuthenticatedUser = true
+		p.PrivateReposAmount = *g.PrivateRepos
+	}
 	return p, nil
 }
 
-func (gh *Github) paginateRepos(ctx context.Context, username string) ([]Repo, error) {
-	var all []Repo
+// paginate walks a list endpoint 100 items at a time until a short page is returned.
+// maxPages stops after that many pages; 0 means no limit.
+func paginate[T any](ctx context.Context, gh *Github, u string, maxPages int) ([]T, error) {
+	sep := "?"
+	if strings.Contains(u, "?") {
+		sep = "&"
+	}
+	var all []T
 	for page := 1; ; page++ {
-		u := fmt.Sprintf("https://api.github.com/users/%s/repos?per_page=100&page=%d", url.PathEscape(username), page)
-		body, err := gh.doRequest(ctx, http.MethodGet, u)
+		body, err := gh.doRequest(ctx, http.MethodGet, fmt.Sprintf("%s%sper_page=100&page=%d", u, sep, page))
 		if err != nil {
 			return nil, err
 		}
-		var repos []Repo
-		if err := json.Unmarshal(body, &repos); err != nil {
-			return nil, fmt.Errorf("unmarshal repos: %w", err)
+		var items []T
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, fmt.Errorf("unmarshal page %d: %w", page, err)
 		}
-		if len(repos) == 0 {
+		if len(items) == 0 {
 			break
 		}
-		all = append(all, repos...)
-		if len(repos) < 100 {
+		all = append(all, items...)
+		if len(items) < 100 || page == maxPages {
 			break
 		}
 	}
 	return all, nil
 }
 
+func (gh *Github) paginateRepos(ctx context.Context, username string) ([]Repo, error) {
+	u := fmt.Sprintf("https://api.github.com/users/%s/repos", url.PathEscape(username))
+	return paginate[Repo](ctx, gh, u, 0)
+}
+
 func (gh *Github) GetRepos(ctx context.Context, username string) ([]Repo, error) {
 	if username == "" {
 		return nil, errors.New("username is required")
@@ -159,15 +323,51 @@ func (gh *Github) GetRepos(ctx context.Context, username string) ([]Repo, error)
 	return gh.paginateRepos(ctx, username)
 }
 
+// GetOwnRepos returns every repo the token owner can access as owner, collaborator
+// or organization member, private ones included.
+func (gh *Github) GetOwnRepos(ctx context.Context) ([]Repo, error) {
+	if gh.Token == "" {
+		return nil, errors.New("github: a token is required to list your own repos")
+	}
+	u := "https://api.github.com/user/repos?visibility=all&affiliation=owner,collaborator,organization_member"
+	return paginate[Repo](ctx, gh, u, 0)
+}
+
+// OwnedBy reports whether r belongs to login rather than to an organization or
+// another user the login collaborates with.
+func (r Repo) OwnedBy(login string) bool {
+	owner, _, _ := strings.Cut(r.FullName, "/")
+	return strings.EqualFold(owner, login)
+}
+
+// CountedRepos returns the repos the profile's public stats count: its own
+// public repos. That is every repo of a public listing, while the token owner's
+// listing also has private repos and repos of others.
+func CountedRepos(p *Profile, repos []Repo) []Repo {
+	if !p.IsAuthenticatedUser {
+		return repos
+	}
+	var counted []Repo
+	for _, r := range repos {
+		if !r.Private && r.OwnedBy(p.Name) {
+			counted = append(counted, r)
+		}
+	}
+	return counted
+}
+
+// calcRepoStats sums the stars and forks of the counted repos and, for the token
+// owner, of their own private repos separately.
 func (gh *Github) calcRepoStats(p *Profile, repos []Repo) {
 	totalStars := 0
 	totalForks := 0
-	for _, r := range repos {
+	counted := CountedRepos(p, repos)
+	for _, r := range counted {
 		totalStars += r.StargazersCount
 		totalForks += r.ForksCount
 	}
-	if len(repos) > 0 {
-		avg := float32(totalStars) / float32(len(repos))
+	if len(counted) > 0 {
+		avg := float32(totalStars) / float32(len(counted))
 		p.AvgStarsPerRepo = new(float32)
 		*p.AvgStarsPerRepo = avg
 	}
@@ -175,8 +375,24 @@ func (gh *Github) calcRepoStats(p *Profile, repos []Repo) {
 	*p.TotalStars = totalStars
 	p.TotalForks = new(int)
 	*p.TotalForks = totalForks
+
+	if !p.IsAuthenticatedUser {
+		return
+	}
+	privateStars, privateForks := 0, 0
+	for _, r := range repos {
+		if r.Private && r.OwnedBy(p.Name) {
+			privateStars += r.StargazersCount
+			privateForks += r.ForksCount
+		}
+	}
+	p.PrivateStars = &privateStars
+	p.PrivateForks = &privateForks
 }
 
+// FetchProfileWithRepos fetches a user's public profile and repos. It is
+// public-only even when the token belongs to the user; OwnFetcher lists the
+// token owner's private repos.
 func (gh *Github) FetchProfileWithRepos(ctx context.Context, username string) (*Profile, []Repo, error) {
 	p, err := gh.GetProfile(ctx, username)
 	if err != nil {
@@ -189,3 +405,27 @@ func (gh *Github) FetchProfileWithRepos(ctx context.Context, username string) (*
 	gh.calcRepoStats(p, repos)
 	return p, repos, nil
 }
+
+// OwnFetcher fetches the token owner's profile from /user and every repo they
+// can access from /user/repos, private ones included. Its results must not be
+// shared with anyone but the token owner.
+type OwnFetcher struct {
+	*Github
+}
+
+// FetchProfileWithRepos fetches the token owner, who must be username.
+func (f OwnFetcher) FetchProfileWithRepos(ctx context.Context, username string) (*Profile, []Repo, error) {
+	p, err := f.GetAuthenticatedProfile(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !strings.EqualFold(p.Name, username) {
+		return nil, nil, fmt.Errorf("github: the token belongs to %s, not %s", p.Name, username)
+	}
+	repos, err := f.GetOwnRepos(ctx)
+	if err != nil {
+		return p, nil, err
+	}
+	f.calcRepoStats(p, repos)
+	return p, repos, nil
+}
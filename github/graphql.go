@@ -0,0 +1,88 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const graphqlURL = "https://api.github.com/graphql"
+
+// ErrNoToken is returned by GraphQL calls when Github.Token is empty; unlike
+// REST, the GraphQL API rejects anonymous requests.
+var ErrNoToken = errors.New("github: a token is required for the GraphQL API")
+
+type graphqlError struct {
+	Message string `json:"message"`
+}
+
+// graphql runs query with vars and decodes the response's data object into out.
+func (gh *Github) graphql(ctx context.Context, query string, vars map[string]any, out any) error {
+	return gh.runGraphQL(ctx, query, vars, out, false)
+}
+
+// graphqlReused is graphql for data that changes slowly, such as pins and the
+// contribution calendar. When ETags is set, a response is reused for
+// GraphQLMaxAge instead of being fetched on every refresh.
+func (gh *Github) graphqlReused(ctx context.Context, query string, vars map[string]any, out any) error {
+	return gh.runGraphQL(ctx, query, vars, out, true)
+}
+
+func (gh *Github) runGraphQL(ctx context.Context, query string, vars map[string]any, out any, reuse bool) error {
+	if gh.Token == "" {
+		return ErrNoToken
+	}
+	payload, err := json.Marshal(map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		return fmt.Errorf("marshal query: %w", err)
+	}
+	reuse = reuse && gh.ETags != nil
+	if reuse {
+		if body, ok := gh.ETags.query(payload); ok {
+			return decodeGraphQL(body, out)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, graphqlURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	body, err := gh.do(req)
+	if err != nil {
+		return err
+	}
+	if err := decodeGraphQL(body, out); err != nil {
+		return err
+	}
+	if reuse {
+		gh.ETags.storeQuery(payload, body)
+	}
+	return nil
+}
+
+// decodeGraphQL decodes a GraphQL response's data object into out, or returns
+// its errors.
+func decodeGraphQL(body []byte, out any) error {
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphqlError  `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("unmarshal graphql response: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		msgs := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			msgs[i] = e.Message
+		}
+		return fmt.Errorf("github: graphql: %s", strings.Join(msgs, "; "))
+	}
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("unmarshal graphql data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Fetcher loads a profile together with its repos and the stats derived from them.
+// *Github implements it over REST and GraphQLFetcher over GraphQL.
+type Fetcher interface {
+	FetchProfileWithRepos(ctx context.Context, username string) (*Profile, []Repo, error)
+}
+
+// GraphQLFetcher fetches the profile, repos (with languages and topics) and pinned
+// items in one GraphQL query, plus one more per additional 100 repos. REST needs
+// one call for the profile and one per 100 repos, without languages or pins.
+type GraphQLFetcher struct {
+	*Github
+}
+
+// NewFetcher returns the fetcher for api ("rest" or "graphql"). GraphQL needs a
+// token, so without one the REST fetcher is returned along with ErrNoToken to let
+// the caller report the fallback.
+func NewFetcher(gh *Github, api string) (Fetcher, error) {
+	switch api {
+	case "", "rest":
+		return gh, nil
+	case "graphql":
+		if gh.Token == "" {
+			return gh, ErrNoToken
+		}
+		return GraphQLFetcher{gh}, nil
+	default:
+		return nil, fmt.Errorf("unknown api %q (want rest or graphql)", api)
+	}
+}
+
+// repoFieldsFragment asks for up to 100 languages per repo, the API's maximum,
+// so byte breakdowns match the REST languages endpoint, which returns them all.
+const repoFieldsFragment = `fragment RepoFields on Repository {
+  databaseId name nameWithOwner url description
+  stargazerCount forkCount watchers { totalCount }
+  primaryLanguage { name }
+  diskUsage
+  issues(states: OPEN) { totalCount }
+  createdAt updatedAt pushedAt isFork isArchived isTemplate mirrorUrl
+  isPrivate visibility homepageUrl
+  pages: deployments(environments: ["github-pages"], first: 1) { totalCount }
+  licenseInfo { key name spdxId }
+  parent { nameWithOwner url stargazerCount }
+  defaultBranchRef { name }
+  repositoryTopics(first: 20) { nodes { topic { name } } }
+  languages(first: 100, orderBy: {field: SIZE, direction: DESC}) { edges { size node { name } } }
+}`
+
+const profileWithReposQuery = `query($login: String!) {
+  user(login: $login) {
+    login name avatarUrl url company location websiteUrl bio twitterUsername email createdAt isHireable
+    followers { totalCount }
+    following { totalCount }
+    gists(privacy: PUBLIC) { totalCount }
+    repositories(first: 100, ownerAffiliations: OWNER, privacy: PUBLIC, orderBy: {field: STARGAZERS, direction: DESC}) {
+      totalCount
+      pageInfo { hasNextPage endCursor }
+      nodes { ...RepoFields }
+    }
+    pinnedItems(first: 6, types: REPOSITORY) { nodes { ... on Repository { ...RepoFields } } }
+  }
+}
+` + repoFieldsFragment
+
+const reposPageQuery = `query($login: String!, $cursor: String) {
+  user(login: $login) {
+    repositories(first: 100, after: $cursor, ownerAffiliations: OWNER, privacy: PUBLIC, orderBy: {field: STARGAZERS, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes { ...RepoFields }
+    }
+  }
+}
+` + repoFieldsFragment
+
+type gqlCount struct {
+	TotalCount int `json:"totalCount"`
+}
+
+type gqlRepo struct {
+	DatabaseID      int      `json:"databaseId"`
+	Name            string   `json:"name"`
+	NameWithOwner   string   `json:"nameWithOwner"`
+	URL             string   `json:"url"`
+	Description     string   `json:"description"`
+	StargazerCount  int      `json:"stargazerCount"`
+	ForkCount       int      `json:"forkCount"`
+	Watchers        gqlCount `json:"watchers"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	DiskUsage   int      `json:"diskUsage"`
+	Issues      gqlCount `json:"issues"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+	PushedAt    string   `json:"pushedAt"`
+	IsFork      bool     `json:"isFork"`
+	IsArchived  bool     `json:"isArchived"`
+	IsTemplate  bool     `json:"isTemplate"`
+	MirrorURL   string   `json:"mirrorUrl"`
+	IsPrivate   bool     `json:"isPrivate"`
+	Visibility  string   `json:"visibility"`
+	HomepageURL string   `json:"homepageUrl"`
+	// Pages counts deployments to the github-pages environment, which every
+	// Pages build makes; GraphQL has no has_pages field.
+	Pages       gqlCount `json:"pages"`
+	LicenseInfo *struct {
+		Key    string `json:"key"`
+		Name   string `json:"name"`
+		SPDXID string `json:"spdxId"`
+	} `json:"licenseInfo"`
+	Parent *struct {
+		NameWithOwner  string `json:"nameWithOwner"`
+		URL            string `json:"url"`
+		StargazerCount int    `json:"stargazerCount"`
+	} `json:"parent"`
+	DefaultBranchRef *struct {
+		Name string `json:"name"`
+	} `json:"defaultBranchRef"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+	Languages struct {
+		Edges []struct {
+			Size int `json:"size"`
+			Node struct {
+				Name string `json:"name"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"languages"`
+}
+
+type gqlRepoPage struct {
+	TotalCount int `json:"totalCount"`
+	PageInfo   struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []gqlRepo `json:"nodes"`
+}
+
+func (r gqlRepo) toRepo() Repo {
+	repo := Repo{
+		ID:              r.DatabaseID,
+		Name:            r.Name,
+		FullName:        r.NameWithOwner,
+		HTMLURL:         r.URL,
+		Description:     r.Description,
+		StargazersCount: r.StargazerCount,
+		ForksCount:      r.ForkCount,
+		WatchersCount:   r.Watchers.TotalCount,
+		Size:            r.DiskUsage,
+		OpenIssuesCount: r.Issues.TotalCount,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+		PushedAt:        r.PushedAt,
+		Fork:            r.IsFork,
+		Archived:        r.IsArchived,
+		IsTemplate:      r.IsTemplate,
+		MirrorURL:       r.MirrorURL,
+		Private:         r.IsPrivate,
+		Visibility:      strings.ToLower(r.Visibility),
+		Homepage:        r.HomepageURL,
+		HasPages:        r.Pages.TotalCount > 0,
+	}
+	if r.LicenseInfo != nil {
+		repo.License = &License{Key: r.LicenseInfo.Key, Name: r.LicenseInfo.Name, SPDXID: r.LicenseInfo.SPDXID}
+	}
+	if r.Parent != nil {
+		repo.Parent = &Repo{FullName: r.Parent.NameWithOwner, HTMLURL: r.Parent.URL, StargazersCount: r.Parent.StargazerCount}
+	}
+	if r.PrimaryLanguage != nil {
+		repo.Language = r.PrimaryLanguage.Name
+	}
+	if r.DefaultBranchRef != nil {
+		repo.DefaultBranch = r.DefaultBranchRef.Name
+	}
+	for _, n := range r.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, n.Topic.Name)
+	}
+	if len(r.Languages.Edges) > 0 {
+		repo.Languages = map[string]int{}
+		for _, e := range r.Languages.Edges {
+			repo.Languages[e.Node.Name] = e.Size
+		}
+	}
+	return repo
+}
+
+func (f GraphQLFetcher) FetchProfileWithRepos(ctx context.Context, username string) (*Profile, []Repo, error) {
+	if username == "" {
+		return nil, nil, errors.New("username is required")
+	}
+	var data struct {
+		User *struct {
+			Login           string      `json:"login"`
+			Name            string      `json:"name"`
+			AvatarURL       string      `json:"avatarUrl"`
+			URL             string      `json:"url"`
+			Company         string      `json:"company"`
+			Location        string      `json:"location"`
+			WebsiteURL      string      `json:"websiteUrl"`
+			Bio             string      `json:"bio"`
+			TwitterUsername string      `json:"twitterUsername"`
+			Email           string      `json:"email"`
+			CreatedAt       string      `json:"createdAt"`
+			IsHireable      bool        `json:"isHireable"`
+			Followers       gqlCount    `json:"followers"`
+			Following       gqlCount    `json:"following"`
+			Gists           gqlCount    `json:"gists"`
+			Repositories    gqlRepoPage `json:"repositories"`
+			PinnedItems     struct {
+				Nodes []gqlRepo `json:"nodes"`
+			} `json:"pinnedItems"`
+		} `json:"user"`
+	}
+	if err := f.graphql(ctx, profileWithReposQuery, map[string]any{"login": username}, &data); err != nil {
+		return nil, nil, err
+	}
+	u := data.User
+	if u == nil {
+		return nil, nil, fmt.Errorf("github: user %q not found", username)
+	}
+	p := &Profile{
+		Name:              u.Login,
+		AvatarURL:         u.AvatarURL,
+		URL:               u.URL,
+		FullName:          u.Name,
+		Company:           u.Company,
+		Location:          u.Location,
+		Blog:              u.WebsiteURL,
+		Bio:               u.Bio,
+		Twitter:           u.TwitterUsername,
+		FollowersAmount:   u.Followers.TotalCount,
+		FollowingAmount:   u.Following.TotalCount,
+		MemberSince:       u.CreatedAt,
+		Hireable:          u.IsHireable,
+		Email:             u.Email,
+		PublicReposAmount: u.Repositories.TotalCount,
+		PublicGistsAmount: u.Gists.TotalCount,
+		Pinned:            pinnedRepos(u.PinnedItems.Nodes),
+	}
+
+	var repos []Repo
+	page := u.Repositories
+	for {
+		for _, n := range page.Nodes {
+			repos = append(repos, n.toRepo())
+		}
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		var next struct {
+			User struct {
+				Repositories gqlRepoPage `json:"repositories"`
+			} `json:"user"`
+		}
+		vars := map[string]any{"login": username, "cursor": page.PageInfo.EndCursor}
+		if err := f.graphql(ctx, reposPageQuery, vars, &next); err != nil {
+			return p, nil, err
+		}
+		page = next.User.Repositories
+	}
+	f.calcRepoStats(p, repos)
+	return p, repos, nil
+}
+
+const pinnedQuery = `query($login: String!) {
+  user(login: $login) {
+    pinnedItems(first: 6, types: REPOSITORY) { nodes { ... on Repository { ...RepoFields } } }
+  }
+}
+` + repoFieldsFragment
+
+// GetPinned returns the repositories pinned on the user's profile, in display
+// order. Pins are only exposed over GraphQL, so this needs a token.
+func (gh *Github) GetPinned(ctx context.Context, username string) ([]Repo, error) {
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+	var data struct {
+		User *struct {
+			PinnedItems struct {
+				Nodes []gqlRepo `json:"nodes"`
+			} `json:"pinnedItems"`
+		} `json:"user"`
+	}
+	if err := gh.graphqlReused(ctx, pinnedQuery, map[string]any{"login": username}, &data); err != nil {
+		return nil, err
+	}
+	if data.User == nil {
+		return nil, fmt.Errorf("github: user %q not found", username)
+	}
+	return pinnedRepos(data.User.PinnedItems.Nodes), nil
+}
+
+// pinnedRepos converts pinned items, dropping pinned gists which come back as
+// empty objects since only repositories are selected.
+func pinnedRepos(nodes []gqlRepo) []Repo {
+	var repos []Repo
+	for _, n := range nodes {
+		if n.NameWithOwner != "" {
+			repos = append(repos, n.toRepo())
+		}
+	}
+	return repos
+}
@@ -0,0 +1,105 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// LanguageConcurrency bounds how many per-repo requests FillLanguages and
+// FillParents run at once.
+const LanguageConcurrency = 8
+
+// GetLanguages returns the bytes of code per language for a repo given as "owner/name".
+func (gh *Github) GetLanguages(ctx context.Context, fullName string) (map[string]int, error) {
+	u, err := repoURL(fullName, "/languages")
+	if err != nil {
+		return nil, err
+	}
+	body, err := gh.doRequest(ctx, http.MethodGet, u)
+	if err != nil {
+		return nil, err
+	}
+	langs := map[string]int{}
+	if err := json.Unmarshal(body, &langs); err != nil {
+		return nil, fmt.Errorf("unmarshal languages: %w", err)
+	}
+	return langs, nil
+}
+
+// ReuseLanguages copies language breakdowns from previously cached repos onto
+// repos that haven't been pushed to since, so they needn't be fetched again.
+func ReuseLanguages(repos, cached []Repo) {
+	prev := make(map[string]Repo, len(cached))
+	for _, r := range cached {
+		if r.Languages != nil {
+			prev[r.FullName] = r
+		}
+	}
+	for i := range repos {
+		if repos[i].Languages != nil {
+			continue
+		}
+		if c, ok := prev[repos[i].FullName]; ok && c.PushedAt == repos[i].PushedAt {
+			repos[i].Languages = c.Languages
+		}
+	}
+}
+
+// FillLanguages fetches the language breakdown of every repo that doesn't have one
+// yet, at most LanguageConcurrency at a time. Repos that fail are left without
+// languages and the first error is returned.
+func (gh *Github) FillLanguages(ctx context.Context, repos []Repo) error {
+	return fillRepos(repos, func(r *Repo) bool { return r.Languages == nil }, func(r *Repo) error {
+		langs, err := gh.GetLanguages(ctx, r.FullName)
+		if err != nil {
+			return fmt.Errorf("languages for %s: %w", r.FullName, err)
+		}
+		r.Languages = langs
+		return nil
+	})
+}
+
+// fillRepos runs fill on every repo that needs it, at most LanguageConcurrency
+// at a time, and returns the first error.
+func fillRepos(repos []Repo, need func(r *Repo) bool, fill func(r *Repo) error) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, LanguageConcurrency)
+	for i := range repos {
+		if !need(&repos[i]) {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *Repo) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fill(r); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(&repos[i])
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// LanguageBytes sums bytes per language across repos.
+func LanguageBytes(repos []Repo) map[string]int {
+	total := map[string]int{}
+	for _, r := range repos {
+		for lang, n := range r.Languages {
+			total[lang] += n
+		}
+	}
+	return total
+}
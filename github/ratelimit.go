@@ -0,0 +1,101 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is the rate limit state GitHub reported on the latest response.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// RateLimitError is returned when GitHub refuses a request because the rate
+// limit is exhausted. Reset is when requests will be accepted again.
+type RateLimitError struct {
+	Limit int
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Reset.IsZero() {
+		return "github: API rate limit exceeded"
+	}
+	return fmt.Sprintf("github: API rate limit of %d requests exceeded; resets at %s", e.Limit, e.Reset.Local().Format("15:04:05"))
+}
+
+// parseRateLimit reads the X-RateLimit-* headers. ok is false when they are missing.
+func parseRateLimit(h http.Header) (rl RateLimit, ok bool) {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return rl, false
+	}
+	rl.Remaining = remaining
+	rl.Limit, _ = strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rl.Reset = time.Unix(reset, 0)
+	}
+	return rl, true
+}
+
+// rateLimitError returns a *RateLimitError if resp is a rate limit refusal:
+// a 403 or 429 with no requests remaining, or a 429 with Retry-After.
+func rateLimitError(resp *http.Response) *RateLimitError {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+	if rl, ok := parseRateLimit(resp.Header); ok && rl.Remaining == 0 {
+		return &RateLimitError{Limit: rl.Limit, Reset: rl.Reset}
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return &RateLimitError{Reset: time.Now().Add(time.Duration(secs) * time.Second)}
+	}
+	return nil
+}
+
+// RateLimit returns the rate limit reported on the latest response. ok is false
+// before any response carried rate limit headers.
+func (gh *Github) RateLimit() (rl RateLimit, ok bool) {
+	gh.rateMu.Lock()
+	defer gh.rateMu.Unlock()
+	return gh.rate, gh.rate.Limit > 0
+}
+
+func (gh *Github) recordRateLimit(h http.Header) {
+	rl, ok := parseRateLimit(h)
+	if !ok {
+		return
+	}
+	gh.rateMu.Lock()
+	gh.rate = rl
+	gh.rateMu.Unlock()
+}
+
+// GetRateLimit asks GitHub for the current REST rate limit. The call itself
+// doesn't count against the limit.
+func (gh *Github) GetRateLimit(ctx context.Context) (RateLimit, error) {
+	body, err := gh.doRequest(ctx, http.MethodGet, "https://api.github.com/rate_limit")
+	if err != nil {
+		return RateLimit{}, err
+	}
+	var resp struct {
+		Resources struct {
+			Core struct {
+				Limit     int   `json:"limit"`
+				Remaining int   `json:"remaining"`
+				Reset     int64 `json:"reset"`
+			} `json:"core"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return RateLimit{}, fmt.Errorf("unmarshal rate limit: %w", err)
+	}
+	core := resp.Resources.Core
+	return RateLimit{Limit: core.Limit, Remaining: core.Remaining, Reset: time.Unix(core.Reset, 0)}, nil
+}
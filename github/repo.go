@@ -0,0 +1,301 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	// MaxContributors is how many of the top contributors FetchRepoDetail fetches.
+	MaxContributors = 10
+	// MaxCommits is how many of the latest commits on the default branch it
+	// fetches, all of which the repo card lists.
+	MaxCommits = 5
+)
+
+// Contributor is a user who committed to a repo, with their commit count.
+type Contributor struct {
+	Login         string `json:"login,omitempty"`
+	HTMLURL       string `json:"html_url,omitempty"`
+	Type          string `json:"type,omitempty"`
+	Contributions int    `json:"contributions,omitempty"`
+}
+
+// Release is a published release of a repo.
+type Release struct {
+	TagName     string `json:"tag_name,omitempty"`
+	Name        string `json:"name,omitempty"`
+	HTMLURL     string `json:"html_url,omitempty"`
+	PublishedAt string `json:"published_at,omitempty"`
+	Prerelease  bool   `json:"prerelease,omitempty"`
+}
+
+// Commit is a commit on a repo's default branch.
+type Commit struct {
+	SHA     string `json:"sha,omitempty"`
+	HTMLURL string `json:"html_url,omitempty"`
+	// Message is the first line of the commit message.
+	Message string `json:"message,omitempty"`
+	// Author is the author's login, or their git name when GitHub can't tell
+	// which user they are.
+	Author string `json:"author,omitempty"`
+	Date   string `json:"date,omitempty"`
+}
+
+// RepoDetail is a repo with everything the repo card shows about it.
+type RepoDetail struct {
+	Repo
+	Contributors  []Contributor `json:"contributors,omitempty"`
+	LatestRelease *Release      `json:"latest_release,omitempty"`
+	// OpenIssues and OpenPulls split Repo.OpenIssuesCount, which counts both.
+	// They are nil when the split couldn't be fetched.
+	OpenIssues *int     `json:"open_issues,omitempty"`
+	OpenPulls  *int     `json:"open_pulls,omitempty"`
+	Commits    []Commit `json:"commits,omitempty"`
+}
+
+// ParseRepoName splits "owner/name" into its parts. A github.com URL to the
+// repo is accepted too.
+func ParseRepoName(fullName string) (owner, name string, err error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(fullName, "https://"), "github.com/")
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".git")
+	owner, name, ok := strings.Cut(s, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid repo name %q (want owner/name)", fullName)
+	}
+	return owner, name, nil
+}
+
+// repoURL returns the API URL of a repo given as "owner/name", with path
+// appended.
+func repoURL(fullName, path string) (string, error) {
+	owner, name, err := ParseRepoName(fullName)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s%s", url.PathEscape(owner), url.PathEscape(name), path), nil
+}
+
+// GetRepo returns a repo given as "owner/name".
+func (gh *Github) GetRepo(ctx context.Context, fullName string) (*Repo, error) {
+	u, err := repoURL(fullName, "")
+	if err != nil {
+		return nil, err
+	}
+	body, err := gh.doRequest(ctx, http.MethodGet, u)
+	if err != nil {
+		return nil, err
+	}
+	var r Repo
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("unmarshal repo: %w", err)
+	}
+	return &r, nil
+}
+
+// FillParents looks up the parent of every fork that doesn't have one, as the
+// REST repo lists leave it out. Forks that fail are left without a parent and
+// the first error is returned.
+func (gh *Github) FillParents(ctx context.Context, repos []Repo) error {
+	return fillRepos(repos, func(r *Repo) bool { return r.Fork && r.Parent == nil }, func(r *Repo) error {
+		full, err := gh.GetRepo(ctx, r.FullName)
+		if err != nil {
+			return fmt.Errorf("parent of %s: %w", r.FullName, err)
+		}
+		if p := full.Parent; p != nil {
+			r.Parent = &Repo{FullName: p.FullName, HTMLURL: p.HTMLURL, StargazersCount: p.StargazersCount}
+		}
+		return nil
+	})
+}
+
+// ReuseParents copies fork parents from previously cached repos, as a fork's
+// parent never changes.
+func ReuseParents(repos, cached []Repo) {
+	prev := make(map[string]*Repo, len(cached))
+	for _, r := range cached {
+		if r.Parent != nil {
+			prev[r.FullName] = r.Parent
+		}
+	}
+	for i := range repos {
+		if repos[i].Fork && repos[i].Parent == nil {
+			repos[i].Parent = prev[repos[i].FullName]
+		}
+	}
+}
+
+// GetContributors returns a repo's top n contributors by commits.
+func (gh *Github) GetContributors(ctx context.Context, fullName string, n int) ([]Contributor, error) {
+	u, err := repoURL(fullName, fmt.Sprintf("/contributors?per_page=%d", n))
+	if err != nil {
+		return nil, err
+	}
+	body, err := gh.doRequest(ctx, http.MethodGet, u)
+	// An empty repo has no contributors and answers 204 No Content.
+	if hasStatus(err, http.StatusNoContent) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var contributors []Contributor
+	if err := json.Unmarshal(body, &contributors); err != nil {
+		return nil, fmt.Errorf("unmarshal contributors: %w", err)
+	}
+	return contributors, nil
+}
+
+// GetLatestRelease returns a repo's latest release, or nil when it has none.
+func (gh *Github) GetLatestRelease(ctx context.Context, fullName string) (*Release, error) {
+	u, err := repoURL(fullName, "/releases/latest")
+	if err != nil {
+		return nil, err
+	}
+	body, err := gh.doRequest(ctx, http.MethodGet, u)
+	if hasStatus(err, http.StatusNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rel Release
+	if err := json.Unmarshal(body, &rel); err != nil {
+		return nil, fmt.Errorf("unmarshal release: %w", err)
+	}
+	return &rel, nil
+}
+
+// GetOpenPullCount returns how many pull requests are open on a repo, using
+// the search API so they needn't all be listed.
+func (gh *Github) GetOpenPullCount(ctx context.Context, fullName string) (int, error) {
+	owner, name, err := ParseRepoName(fullName)
+	if err != nil {
+		return 0, err
+	}
+	q := url.QueryEscape(fmt.Sprintf("repo:%s/%s type:pr state:open", owner, name))
+	body, err := gh.doRequest(ctx, http.MethodGet, "https://api.github.com/search/issues?per_page=1&q="+q)
+	if err != nil {
+		return 0, err
+	}
+	var res struct {
+		TotalCount int `json:"total_count"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return 0, fmt.Errorf("unmarshal search: %w", err)
+	}
+	return res.TotalCount, nil
+}
+
+// GetCommits returns the latest n commits on a repo's default branch.
+func (gh *Github) GetCommits(ctx context.Context, fullName string, n int) ([]Commit, error) {
+	u, err := repoURL(fullName, fmt.Sprintf("/commits?per_page=%d", n))
+	if err != nil {
+		return nil, err
+	}
+	body, err := gh.doRequest(ctx, http.MethodGet, u)
+	// An empty repo has no branch to list and answers 409 Conflict.
+	if hasStatus(err, http.StatusConflict) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var raw []struct {
+		SHA     string `json:"sha"`
+		HTMLURL string `json:"html_url"`
+		Commit  struct {
+			Message string `json:"message"`
+			Author  struct {
+				Name string `json:"name"`
+				Date string `json:"date"`
+			} `json:"author"`
+		} `json:"commit"`
+		Author *struct {
+			Login string `json:"login"`
+		} `json:"author"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal commits: %w", err)
+	}
+	commits := make([]Commit, len(raw))
+	for i, c := range raw {
+		message, _, _ := strings.Cut(c.Commit.Message, "\n")
+		author := c.Commit.Author.Name
+		if c.Author != nil && c.Author.Login != "" {
+			author = c.Author.Login
+		}
+		commits[i] = Commit{SHA: c.SHA, HTMLURL: c.HTMLURL, Message: strings.TrimSpace(message), Author: author, Date: c.Commit.Author.Date}
+	}
+	return commits, nil
+}
+
+// FetchRepoDetail fetches a repo given as "owner/name" with its languages, top
+// contributors, latest release, open issue and pull request counts and recent
+// commits. Only failing to fetch the repo itself is fatal: the other parts are
+// fetched concurrently, failed ones are left empty and their errors are
+// returned joined, along with the detail.
+func (gh *Github) FetchRepoDetail(ctx context.Context, fullName string) (*RepoDetail, error) {
+	r, err := gh.GetRepo(ctx, fullName)
+	if err != nil {
+		return nil, err
+	}
+	d := &RepoDetail{Repo: *r}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	fetch := func(what string, f func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := f(); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", what, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	// Fetch by the canonical name, in case fullName was a URL or old name.
+	name := r.FullName
+	fetch("languages", func() (err error) {
+		d.Languages, err = gh.GetLanguages(ctx, name)
+		return err
+	})
+	fetch("contributors", func() (err error) {
+		d.Contributors, err = gh.GetContributors(ctx, name, MaxContributors)
+		return err
+	})
+	fetch("latest release", func() (err error) {
+		d.LatestRelease, err = gh.GetLatestRelease(ctx, name)
+		return err
+	})
+	fetch("open pull requests", func() error {
+		pulls, err := gh.GetOpenPullCount(ctx, name)
+		if err != nil {
+			return err
+		}
+		issues := max(r.OpenIssuesCount-pulls, 0)
+		d.OpenIssues, d.OpenPulls = &issues, &pulls
+		return nil
+	})
+	fetch("commits", func() (err error) {
+		d.Commits, err = gh.GetCommits(ctx, name, MaxCommits)
+		return err
+	})
+	wg.Wait()
+	return d, errors.Join(errs...)
+}
+
+// hasStatus reports whether err is a StatusError with the given status code.
+func hasStatus(err error, code int) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.StatusCode == code
+}
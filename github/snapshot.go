@@ -0,0 +1,191 @@
+package github
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// Snapshot records a user's headline numbers at one point in time. Snapshots
+// are appended to a per-user JSON Lines file so changes can be tracked.
+type Snapshot struct {
+	Time        time.Time      `json:"time"`
+	Followers   int            `json:"followers"`
+	Following   int            `json:"following"`
+	PublicRepos int            `json:"public_repos"`
+	TotalStars  int            `json:"total_stars"`
+	TotalForks  int            `json:"total_forks"`
+	Repos       []RepoSnapshot `json:"repos,omitempty"`
+}
+
+type RepoSnapshot struct {
+	FullName string `json:"full_name"`
+	Stars    int    `json:"stars"`
+	Forks    int    `json:"forks"`
+}
+
+// NewSnapshot records p at time at. Only the counted repos are recorded, so the
+// token owner's private repos and repos of others stay out of the totals.
+func NewSnapshot(p *Profile, repos []Repo, at time.Time) Snapshot {
+	s := Snapshot{
+		Time:        at.UTC(),
+		Followers:   p.FollowersAmount,
+		Following:   p.FollowingAmount,
+		PublicRepos: p.PublicReposAmount,
+	}
+	for _, r := range CountedRepos(p, repos) {
+		s.TotalStars += r.StargazersCount
+		s.TotalForks += r.ForksCount
+		s.Repos = append(s.Repos, RepoSnapshot{FullName: r.FullName, Stars: r.StargazersCount, Forks: r.ForksCount})
+	}
+	return s
+}
+
+func SnapshotPath(user string) (string, error) {
+	base, err := cacheDir("snapshots")
+	if err != nil {
+		return "", err
+	}
+	return base + "/" + user + ".jsonl", nil
+}
+
+// AppendSnapshot adds s to the end of the user's snapshot file.
+func AppendSnapshot(user string, s Snapshot) error {
+	path, err := SnapshotPath(user)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ErrNoSnapshots is returned by LoadSnapshots when nothing was recorded for a user.
+var ErrNoSnapshots = errors.New("no snapshots recorded")
+
+// LoadSnapshots returns the user's snapshots, oldest first.
+func LoadSnapshots(user string) ([]Snapshot, error) {
+	path, err := SnapshotPath(user)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoSnapshots
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var snaps []Snapshot
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var s Snapshot
+		if err := json.Unmarshal(sc.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		snaps = append(snaps, s)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(snaps) == 0 {
+		return nil, ErrNoSnapshots
+	}
+	sort.SliceStable(snaps, func(i, j int) bool { return snaps[i].Time.Before(snaps[j].Time) })
+	return snaps, nil
+}
+
+// SnapshotDiff describes what changed between two snapshots.
+type SnapshotDiff struct {
+	From           time.Time   `json:"from"`
+	To             time.Time   `json:"to"`
+	Followers      int         `json:"followers"`
+	FollowersDelta int         `json:"followers_delta"`
+	Stars          int         `json:"stars"`
+	StarsDelta     int         `json:"stars_delta"`
+	Forks          int         `json:"forks"`
+	ForksDelta     int         `json:"forks_delta"`
+	NewRepos       []string    `json:"new_repos,omitempty"`
+	RemovedRepos   []string    `json:"removed_repos,omitempty"`
+	RepoDeltas     []RepoDelta `json:"repo_deltas,omitempty"`
+}
+
+// RepoDelta is the change in stars and forks of a repo present in both snapshots.
+type RepoDelta struct {
+	FullName   string `json:"full_name"`
+	Stars      int    `json:"stars"`
+	StarsDelta int    `json:"stars_delta"`
+	ForksDelta int    `json:"forks_delta"`
+}
+
+// DiffSnapshots compares from with to. Repo deltas only list repos whose stars or
+// forks changed, biggest star gain first.
+func DiffSnapshots(from, to Snapshot) SnapshotDiff {
+	d := SnapshotDiff{
+		From:           from.Time,
+		To:             to.Time,
+		Followers:      to.Followers,
+		FollowersDelta: to.Followers - from.Followers,
+		Stars:          to.TotalStars,
+		StarsDelta:     to.TotalStars - from.TotalStars,
+		Forks:          to.TotalForks,
+		ForksDelta:     to.TotalForks - from.TotalForks,
+	}
+	before := make(map[string]RepoSnapshot, len(from.Repos))
+	for _, r := range from.Repos {
+		before[r.FullName] = r
+	}
+	after := make(map[string]bool, len(to.Repos))
+	for _, r := range to.Repos {
+		after[r.FullName] = true
+		old, ok := before[r.FullName]
+		if !ok {
+			d.NewRepos = append(d.NewRepos, r.FullName)
+			continue
+		}
+		if r.Stars != old.Stars || r.Forks != old.Forks {
+			d.RepoDeltas = append(d.RepoDeltas, RepoDelta{FullName: r.FullName, Stars: r.Stars, StarsDelta: r.Stars - old.Stars, ForksDelta: r.Forks - old.Forks})
+		}
+	}
+	for _, r := range from.Repos {
+		if !after[r.FullName] {
+			d.RemovedRepos = append(d.RemovedRepos, r.FullName)
+		}
+	}
+	sort.Strings(d.NewRepos)
+	sort.Strings(d.RemovedRepos)
+	sort.SliceStable(d.RepoDeltas, func(i, j int) bool { return d.RepoDeltas[i].StarsDelta > d.RepoDeltas[j].StarsDelta })
+	return d
+}
+
+// SnapshotBefore returns the latest snapshot taken at or before t, or the oldest
+// one if all are newer. snaps must be sorted oldest first and not be empty.
+func SnapshotBefore(snaps []Snapshot, t time.Time) Snapshot {
+	best := snaps[0]
+	for _, s := range snaps {
+		if s.Time.After(t) {
+			break
+		}
+		best = s
+	}
+	return best
+}
@@ -0,0 +1,52 @@
+package github
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortKeys lists the keys SortRepos understands.
+var SortKeys = []string{"stars", "forks", "watchers", "issues", "size", "created", "updated", "pushed", "name"}
+
+// SortRepos orders repos in place by key, highest (or newest, or last by name)
+// first unless ascending is set. Unknown keys sort by stars. Ties keep their
+// existing order.
+func SortRepos(repos []Repo, by string, ascending bool) {
+	var less func(a, b Repo) bool
+	switch by {
+	case "forks":
+		less = func(a, b Repo) bool { return a.ForksCount < b.ForksCount }
+	case "watchers":
+		less = func(a, b Repo) bool { return a.WatchersCount < b.WatchersCount }
+	case "issues":
+		less = func(a, b Repo) bool { return a.OpenIssuesCount < b.OpenIssuesCount }
+	case "size":
+		less = func(a, b Repo) bool { return a.Size < b.Size }
+	// Timestamps are RFC 3339 in UTC, so they order correctly as strings.
+	case "created":
+		less = func(a, b Repo) bool { return a.CreatedAt < b.CreatedAt }
+	case "updated":
+		less = func(a, b Repo) bool { return a.UpdatedAt < b.UpdatedAt }
+	case "pushed":
+		less = func(a, b Repo) bool { return a.PushedAt < b.PushedAt }
+	case "name":
+		less = func(a, b Repo) bool { return strings.ToLower(a.FullName) < strings.ToLower(b.FullName) }
+	default:
+		less = func(a, b Repo) bool { return a.StargazersCount < b.StargazersCount }
+	}
+	if ascending {
+		sort.SliceStable(repos, func(i, j int) bool { return less(repos[i], repos[j]) })
+		return
+	}
+	sort.SliceStable(repos, func(i, j int) bool { return less(repos[j], repos[i]) })
+}
+
+// IsSortKey reports whether key is one of SortKeys.
+func IsSortKey(key string) bool {
+	for _, k := range SortKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// starMediaType makes the starred endpoint wrap each repo with the time it was starred.
+const starMediaType = "application/vnd.github.star+json"
+
+// MaxStarred is how many of the most recent stars GetStarred fetches.
+const MaxStarred = 100
+
+type StarredRepo struct {
+	StarredAt string `json:"starred_at,omitempty"`
+	Repo      Repo   `json:"repo"`
+}
+
+// StarredStats counts languages and topics across starred repos.
+type StarredStats struct {
+	Languages map[string]int
+	Topics    map[string]int
+}
+
+func CalcStarredStats(starred []StarredRepo) StarredStats {
+	st := StarredStats{Languages: map[string]int{}, Topics: map[string]int{}}
+	for _, s := range starred {
+		if s.Repo.Language != "" {
+			st.Languages[s.Repo.Language]++
+		}
+		for _, t := range s.Repo.Topics {
+			st.Topics[t]++
+		}
+	}
+	return st
+}
+
+// GetStarred returns the repositories a user starred most recently, newest first.
+// Only the latest MaxStarred stars are fetched; users can have many thousands.
+func (gh *Github) GetStarred(ctx context.Context, username string) ([]StarredRepo, error) {
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+	u := fmt.Sprintf("https://api.github.com/users/%s/starred?sort=created&direction=desc&per_page=%d", url.PathEscape(username), MaxStarred)
+	body, err := gh.doRequestAccept(ctx, http.MethodGet, u, starMediaType)
+	if err != nil {
+		return nil, err
+	}
+	var starred []StarredRepo
+	if err := json.Unmarshal(body, &starred); err != nil {
+		return nil, fmt.Errorf("unmarshal starred: %w", err)
+	}
+	return starred, nil
+}
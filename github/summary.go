@@ -0,0 +1,70 @@
+package github
+
+import "time"
+
+// Summary condenses a profile and its repos into the figures compared across users.
+type Summary struct {
+	Login          string   `json:"login"`
+	Name           string   `json:"name,omitempty"`
+	Followers      int      `json:"followers"`
+	PublicRepos    int      `json:"public_repos"`
+	TotalStars     int      `json:"total_stars"`
+	TotalForks     int      `json:"total_forks"`
+	AvgStars       float64  `json:"avg_stars_per_repo"`
+	TopLanguages   []string `json:"top_languages"`
+	JoinedAt       string   `json:"joined_at,omitempty"`
+	AccountAgeDays int      `json:"account_age_days"`
+	// Languages counts the counted repos per primary language.
+	Languages map[string]int `json:"languages,omitempty"`
+	// Contributions is the contribution calendar total, when it was fetched.
+	Contributions int `json:"contributions,omitempty"`
+}
+
+// maxSummaryLanguages is how many languages Summarize keeps, by repo count.
+const maxSummaryLanguages = 3
+
+// Summarize computes the Summary of p and repos, measuring account age up to now.
+func Summarize(p *Profile, repos []Repo, now time.Time) Summary {
+	s := Summary{
+		Login:       p.Name,
+		Name:        p.FullName,
+		Followers:   p.FollowersAmount,
+		PublicRepos: p.PublicReposAmount,
+	}
+	if p.TotalStars != nil {
+		s.TotalStars = *p.TotalStars
+	}
+	if p.TotalForks != nil {
+		s.TotalForks = *p.TotalForks
+	}
+	if p.AvgStarsPerRepo != nil {
+		s.AvgStars = float64(*p.AvgStarsPerRepo)
+	}
+	if p.Contributions != nil {
+		s.Contributions = p.Contributions.TotalContributions
+	}
+	if joined, ok := p.JoinedAt(); ok {
+		s.JoinedAt = p.MemberSince
+		s.AccountAgeDays = int(now.Sub(joined).Hours() / 24)
+	}
+
+	s.Languages = map[string]int{}
+	counts := map[string]int{}
+	for _, r := range CountedRepos(p, repos) {
+		if r.Language != "" {
+			s.Languages[r.Language]++
+			counts[r.Language]++
+		}
+	}
+	for len(s.TopLanguages) < maxSummaryLanguages && len(counts) > 0 {
+		best := ""
+		for lang, n := range counts {
+			if best == "" || n > counts[best] || (n == counts[best] && lang < best) {
+				best = lang
+			}
+		}
+		s.TopLanguages = append(s.TopLanguages, best)
+		delete(counts, best)
+	}
+	return s
+}
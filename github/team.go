@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+// TeamConcurrency is the default number of users FetchEach fetches at once.
+const TeamConcurrency = 4
+
+// TeamMetrics are the metrics a team leaderboard can be ranked by.
+var TeamMetrics = []string{"stars", "followers", "contributions", "repos"}
+
+// IsTeamMetric reports whether by is one of TeamMetrics.
+func IsTeamMetric(by string) bool {
+	for _, m := range TeamMetrics {
+		if m == by {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTeamMembers returns the logins of an organization team's members. It needs
+// a token that can read the org's teams.
+func (gh *Github) GetTeamMembers(ctx context.Context, org, slug string) ([]string, error) {
+	if org == "" || slug == "" {
+		return nil, errors.New("org and team slug are required")
+	}
+	u := fmt.Sprintf("https://api.github.com/orgs/%s/teams/%s/members", url.PathEscape(org), url.PathEscape(slug))
+	members, err := paginate[struct {
+		Login string `json:"login"`
+	}](ctx, gh, u, 0)
+	if err != nil {
+		return nil, err
+	}
+	logins := make([]string, len(members))
+	for i, m := range members {
+		logins[i] = m.Login
+	}
+	return logins, nil
+}
+
+// FetchEach calls fetch for every user, at most concurrency at a time, and
+// returns the errors by index. Once a call fails with a *RateLimitError the
+// remaining users aren't tried; they get the same error.
+func FetchEach(ctx context.Context, users []string, concurrency int, fetch func(ctx context.Context, i int, user string) error) []error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	errs := make([]error, len(users))
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		limited *RateLimitError
+	)
+	sem := make(chan struct{}, concurrency)
+	for i, user := range users {
+		sem <- struct{}{}
+		mu.Lock()
+		stop := limited
+		mu.Unlock()
+		if stop != nil {
+			<-sem
+			errs[i] = stop
+			continue
+		}
+		wg.Add(1)
+		go func(i int, user string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := fetch(ctx, i, user)
+			var rlErr *RateLimitError
+			if errors.As(err, &rlErr) {
+				mu.Lock()
+				limited = rlErr
+				mu.Unlock()
+			}
+			errs[i] = err
+		}(i, user)
+	}
+	wg.Wait()
+	return errs
+}
+
+// TeamMetric returns the value of metric by for s.
+func TeamMetric(s Summary, by string) int {
+	switch by {
+	case "followers":
+		return s.Followers
+	case "contributions":
+		return s.Contributions
+	case "repos":
+		return s.PublicRepos
+	default:
+		return s.TotalStars
+	}
+}
+
+// RankTeam sorts members by metric, highest first, breaking ties by login.
+func RankTeam(members []Summary, by string) {
+	sort.SliceStable(members, func(i, j int) bool {
+		a, b := TeamMetric(members[i], by), TeamMetric(members[j], by)
+		if a != b {
+			return a > b
+		}
+		return members[i].Login < members[j].Login
+	})
+}
+
+// TeamLanguages sums the per-language repo counts of all members.
+func TeamLanguages(members []Summary) map[string]int {
+	total := map[string]int{}
+	for _, m := range members {
+		for lang, n := range m.Languages {
+			total[lang] += n
+		}
+	}
+	return total
+}
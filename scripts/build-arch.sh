@@ -49,16 +49,16 @@ info "Go version: $(go version)"
 # Build with optimizations: disable cgo, strip debug/symbols via linker flags, and trim paths
 LDFLAGS="-s -w"
 
-info "Running: CGO_ENABLED=0 GOOS=${OS} GOARCH=${GOARCH} ${GOARM:+GOARM=${GOARM}} go build -trimpath -ldflags \"${LDFLAGS}\" -o ${OUT} ./cmd/main.go"
+info "Running: CGO_ENABLED=0 GOOS=${OS} GOARCH=${GOARCH} ${GOARM:+GOARM=${GOARM}} go build -trimpath -ldflags \"${LDFLAGS}\" -o ${OUT} ./cmd"
 if [ -n "$GOARM" ]; then
-  if CGO_ENABLED=0 GOOS=${OS} GOARCH=${GOARCH} GOARM=${GOARM} go build -trimpath -ldflags "${LDFLAGS}" -o ${OUT} ./cmd/main.go; then
+  if CGO_ENABLED=0 GOOS=${OS} GOARCH=${GOARCH} GOARM=${GOARM} go build -trimpath -ldflags "${LDFLAGS}" -o ${OUT} ./cmd; then
     success "Go build completed for ${OS}/${ARCH} (GOARCH=${GOARCH} GOARM=${GOARM})"
   else
     error "Go build failed for ${OS}/${ARCH}"
     exit 1
   fi
 else
-  if CGO_ENABLED=0 GOOS=${OS} GOARCH=${GOARCH} go build -trimpath -ldflags "${LDFLAGS}" -o ${OUT} ./cmd/main.go; then
+  if CGO_ENABLED=0 GOOS=${OS} GOARCH=${GOARCH} go build -trimpath -ldflags "${LDFLAGS}" -o ${OUT} ./cmd; then
     success "Go build completed for ${OS}/${ARCH}"
   else
     error "Go build failed for ${OS}/${ARCH}"
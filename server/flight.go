@@ -0,0 +1,41 @@
+package server
+
+import "sync"
+
+// flightGroup coalesces concurrent calls with the same key into one, so a
+// burst of requests for a user costs a single upstream fetch.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	val  entry
+	err  error
+}
+
+// do runs fn once for key at a time. Callers arriving while it runs wait for and
+// share its result; shared reports whether this caller joined another's call.
+func (g *flightGroup) do(key string, fn func() (entry, error)) (val entry, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-c.done
+		return c.val, c.err, true
+	}
+	c := &flightCall{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.val, c.err = fn()
+	close(c.done)
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	return c.val, c.err, false
+}
@@ -0,0 +1,204 @@
+// Package server serves profile cards and JSON over HTTP.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"ghprofile/github"
+	"ghprofile/ui"
+)
+
+// FetchTimeout bounds one upstream fetch. It is independent of the requests
+// waiting on it, so a client hanging up doesn't cancel the fetch for others.
+const FetchTimeout = 30 * time.Second
+
+// MaxCachedProfiles bounds the in-memory cache. Expired profiles are dropped
+// whenever one is added, and the oldest one when it is still full.
+const MaxCachedProfiles = 1000
+
+// errPrivateProfile is returned for a profile fetched as its owner, which may
+// include private repos and must not be cached or served.
+var errPrivateProfile = errors.New("profile includes the token owner's private data")
+
+// loginPattern matches valid GitHub logins, rejecting anything else before it
+// reaches the API.
+var loginPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9]|-[A-Za-z0-9]){0,38}$`)
+
+// Server answers /u/{user}.{json,svg,md,txt} and /healthz. All requests share
+// one fetcher (and so one token), one in-memory cache and the on-disk cache.
+type Server struct {
+	// Fetcher must fetch public data only, such as the one github.NewFetcher
+	// returns, as its results are served to anyone. Profiles of the token owner
+	// fetched as the owner are refused.
+	Fetcher github.Fetcher
+	// TTL is how long a fetched profile is served before it is fetched again.
+	TTL time.Duration
+	// Options renders the .md and .txt formats.
+	Options ui.Options
+
+	mu     sync.Mutex
+	cache  map[string]entry
+	flight flightGroup
+}
+
+type entry struct {
+	profile *github.Profile
+	repos   []github.Repo
+	fetched time.Time
+}
+
+func New(f github.Fetcher, ttl time.Duration) *Server {
+	return &Server{
+		Fetcher: f,
+		TTL:     ttl,
+		Options: ui.Options{TopN: 5, NoStyle: true, TopBy: "stars"},
+		cache:   map[string]entry{},
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("GET /u/{file}", s.serveUser)
+	return mux
+}
+
+func (s *Server) serveUser(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	ext := path.Ext(file)
+	user := strings.TrimSuffix(file, ext)
+	if !loginPattern.MatchString(user) {
+		http.Error(w, "invalid username", http.StatusBadRequest)
+		return
+	}
+	var contentType string
+	switch ext {
+	case ".json":
+		contentType = "application/json"
+	case ".svg":
+		contentType = "image/svg+xml"
+	case ".md":
+		contentType = "text/markdown; charset=utf-8"
+	case ".txt":
+		contentType = "text/plain; charset=utf-8"
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	e, err := s.get(user)
+	if err != nil {
+		var rlErr *github.RateLimitError
+		var stErr *github.StatusError
+		switch {
+		case errors.As(err, &stErr) && stErr.StatusCode == http.StatusNotFound:
+			http.Error(w, "user not found", http.StatusNotFound)
+		case errors.As(err, &rlErr):
+			if !rlErr.Reset.IsZero() {
+				w.Header().Set("Retry-After", fmt.Sprint(max(1, int(time.Until(rlErr.Reset).Seconds()))))
+			}
+			http.Error(w, "GitHub rate limit exceeded", http.StatusServiceUnavailable)
+		case errors.Is(err, errPrivateProfile):
+			log.Printf("fetch %s: %v", user, err)
+			http.Error(w, "profile not available", http.StatusForbidden)
+		default:
+			log.Printf("fetch %s: %v", user, err)
+			http.Error(w, "upstream fetch failed", http.StatusBadGateway)
+		}
+		return
+	}
+
+	var body []byte
+	switch ext {
+	case ".json":
+		body, err = ui.RenderJSON(e.profile, e.repos)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		body = append(body, '\n')
+	case ".svg":
+		body = []byte(ui.RenderSVG(e.profile, e.repos))
+	case ".md":
+		body = []byte(ui.RenderMarkdown(e.profile, e.repos, s.Options))
+	case ".txt":
+		body = []byte(ui.RenderProfile(e.profile, e.repos, s.Options))
+	}
+	w.Header().Set("Content-Type", contentType)
+	// Downstream caches may keep the response only as long as this entry lasts.
+	maxAge := max(0, int((s.TTL - time.Since(e.fetched)).Seconds()))
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	w.Write(body)
+}
+
+// get returns the user's profile from the in-memory cache, or fetches it with
+// concurrent requests for the same user sharing one fetch. If the fetch fails,
+// the on-disk cache is used when available.
+func (s *Server) get(user string) (entry, error) {
+	key := strings.ToLower(user)
+	s.mu.Lock()
+	e, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && time.Since(e.fetched) < s.TTL {
+		return e, nil
+	}
+	e, err, _ := s.flight.do(key, func() (entry, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), FetchTimeout)
+		defer cancel()
+		p, repos, err := s.Fetcher.FetchProfileWithRepos(ctx, user)
+		if err == nil && p.IsAuthenticatedUser {
+			return entry{}, errPrivateProfile
+		}
+		if err != nil {
+			var stErr *github.StatusError
+			if errors.As(err, &stErr) && stErr.StatusCode == http.StatusNotFound {
+				return entry{}, err
+			}
+			// The CLI caches the token owner's profile with its private repos
+			// under the same name; never serve that one.
+			if cp, cr, cerr := github.TryLoadCache(key); cerr == nil && !cp.IsAuthenticatedUser {
+				log.Printf("fetch %s failed, serving cached data: %v", user, err)
+				return entry{profile: cp, repos: cr, fetched: time.Now()}, nil
+			}
+			return entry{}, err
+		}
+		if err := github.SaveCache(key, p, repos); err != nil {
+			log.Printf("save cache for %s: %v", user, err)
+		}
+		e := entry{profile: p, repos: repos, fetched: time.Now()}
+		s.store(key, e)
+		return e, nil
+	})
+	return e, err
+}
+
+// store adds e to the in-memory cache, first dropping expired entries and, if
+// it is still full, the oldest one.
+func (s *Server) store(key string, e entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var oldest string
+	for k, c := range s.cache {
+		if time.Since(c.fetched) >= s.TTL {
+			delete(s.cache, k)
+		} else if oldest == "" || c.fetched.Before(s.cache[oldest].fetched) {
+			oldest = k
+		}
+	}
+	if _, ok := s.cache[key]; !ok && len(s.cache) >= MaxCachedProfiles {
+		delete(s.cache, oldest)
+	}
+	s.cache[key] = e
+}
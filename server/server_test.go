@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ghprofile/github"
+)
+
+type stubFetcher struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (f *stubFetcher) FetchProfileWithRepos(ctx context.Context, user string) (*github.Profile, []github.Repo, error) {
+	f.calls.Add(1)
+	<-f.release
+	if user == "ghost" {
+		return nil, nil, &github.StatusError{Method: "GET", URL: "/users/ghost", StatusCode: http.StatusNotFound}
+	}
+	if user == "owner" {
+		return &github.Profile{Name: user, IsAuthenticatedUser: true, PrivateReposAmount: 2}, nil, nil
+	}
+	stars := 3
+	return &github.Profile{Name: user, FullName: "Test User", TotalStars: &stars}, []github.Repo{{FullName: user + "/x", Language: "Go", StargazersCount: 3}}, nil
+}
+
+func TestServerCoalescesRequests(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	f := &stubFetcher{release: make(chan struct{})}
+	srv := httptest.NewServer(New(f, time.Minute).Handler())
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+	codes := make([]int, 5)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := http.Get(srv.URL + "/u/alice.json")
+			if err != nil {
+				t.Errorf("request %d: %v", i, err)
+				return
+			}
+			resp.Body.Close()
+			codes[i] = resp.StatusCode
+		}(i)
+	}
+	// Let every request reach the server before the fetch completes.
+	time.Sleep(100 * time.Millisecond)
+	close(f.release)
+	wg.Wait()
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Fatalf("request %d: status %d", i, code)
+		}
+	}
+	if n := f.calls.Load(); n != 1 {
+		t.Fatalf("expected 1 upstream fetch, got %d", n)
+	}
+
+	for _, tc := range []struct {
+		path, contentType, contains string
+	}{
+		{"/u/alice.svg", "image/svg+xml", "<svg"},
+		{"/u/alice.md", "text/markdown; charset=utf-8", "# [Test User]"},
+		{"/u/alice.txt", "text/plain; charset=utf-8", "Total stars:"},
+		{"/healthz", "text/plain; charset=utf-8", "ok"},
+	} {
+		resp, err := http.Get(srv.URL + tc.path)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != tc.contentType || !strings.Contains(string(body), tc.contains) {
+			t.Fatalf("%s: got %d %q %q", tc.path, resp.StatusCode, resp.Header.Get("Content-Type"), body)
+		}
+	}
+	if n := f.calls.Load(); n != 1 {
+		t.Fatalf("expected cached responses, got %d upstream fetches", n)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	f := &stubFetcher{release: make(chan struct{})}
+	close(f.release)
+	srv := httptest.NewServer(New(f, time.Minute).Handler())
+	defer srv.Close()
+
+	for path, want := range map[string]int{
+		"/u/ghost.json": http.StatusNotFound,
+		"/u/owner.json": http.StatusForbidden,
+		"/u/alice.png":  http.StatusNotFound,
+		"/u/-bad-.json": http.StatusBadRequest,
+		"/u/a..b.json":  http.StatusBadRequest,
+		"/nothing-here": http.StatusNotFound,
+	} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("%s: expected %d got %d", path, want, resp.StatusCode)
+		}
+	}
+}
+
+func TestServerNeverCachesOwnerProfiles(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	f := &stubFetcher{release: make(chan struct{})}
+	close(f.release)
+	s := New(f, time.Minute)
+	if _, err := s.get("owner"); !errors.Is(err, errPrivateProfile) {
+		t.Fatalf("expected errPrivateProfile, got %v", err)
+	}
+	if _, _, err := github.TryLoadCache("owner"); err == nil {
+		t.Fatal("owner profile was saved to the disk cache")
+	}
+	if len(s.cache) != 0 {
+		t.Fatalf("owner profile was cached in memory: %v", s.cache)
+	}
+}
+
+func TestServerMaxAgeCountsDown(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	f := &stubFetcher{release: make(chan struct{})}
+	close(f.release)
+	s := New(f, time.Hour)
+	s.cache["alice"] = entry{profile: &github.Profile{Name: "alice"}, fetched: time.Now().Add(-40 * time.Minute)}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/u/alice.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get("Cache-Control"); got != "public, max-age=1200" && got != "public, max-age=1199" {
+		t.Fatalf("expected the remaining 20 minutes as max-age, got %q", got)
+	}
+	if n := f.calls.Load(); n != 0 {
+		t.Fatalf("expected the cached entry to be served, got %d fetches", n)
+	}
+}
+
+func TestServerStoreEvicts(t *testing.T) {
+	s := New(nil, time.Minute)
+	now := time.Now()
+	s.cache["stale"] = entry{fetched: now.Add(-2 * time.Minute)}
+	s.store("fresh", entry{fetched: now})
+	if _, ok := s.cache["stale"]; ok {
+		t.Fatal("expired entry was not evicted")
+	}
+
+	for i := len(s.cache); i < MaxCachedProfiles; i++ {
+		s.cache[fmt.Sprint("user", i)] = entry{fetched: now.Add(time.Duration(i) * time.Millisecond)}
+	}
+	s.store("new", entry{fetched: now})
+	if len(s.cache) != MaxCachedProfiles {
+		t.Fatalf("expected the cache to stay at %d entries, got %d", MaxCachedProfiles, len(s.cache))
+	}
+	if _, ok := s.cache["fresh"]; ok {
+		t.Fatal("expected the oldest entry to be evicted")
+	}
+}
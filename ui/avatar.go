@@ -0,0 +1,281 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Ways the avatar can be drawn. Blocks works in any truecolor terminal; the
+// others are terminal graphics protocols with real pixels.
+const (
+	ImageBlocks = "blocks"
+	ImageKitty  = "kitty"
+	ImageITerm  = "iterm"
+	ImageSixel  = "sixel"
+)
+
+// avatarCols and avatarRows are the cells the avatar takes up next to the name.
+// Cells are about twice as tall as wide, so this is square.
+const (
+	avatarCols = 10
+	avatarRows = 5
+)
+
+// sixelCellWidth and sixelCellHeight approximate a terminal cell in pixels, as
+// sixel images are sized in pixels rather than cells.
+const (
+	sixelCellWidth  = 10
+	sixelCellHeight = 20
+)
+
+// avatarMarker marks where a graphics-protocol avatar goes in the rendered card.
+// It is replaced by a space before printing.
+const avatarMarker = '\uE000'
+
+// Avatar is a decoded profile picture.
+type Avatar struct {
+	img image.Image
+}
+
+// DecodeAvatar decodes a PNG or JPEG avatar.
+func DecodeAvatar(data []byte) (*Avatar, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode avatar: %w", err)
+	}
+	return &Avatar{img: img}, nil
+}
+
+// DetectImageProtocol picks the best way to draw images in the current terminal
+// from its environment. Inside tmux or screen, which need passthrough for
+// graphics, it settles for blocks.
+func DetectImageProtocol() string {
+	termName, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("TMUX") != "" || strings.HasPrefix(termName, "screen") || strings.HasPrefix(termName, "tmux"):
+		return ImageBlocks
+	case os.Getenv("KITTY_WINDOW_ID") != "" || termName == "xterm-kitty" || program == "ghostty":
+		return ImageKitty
+	case program == "iTerm.app" || program == "WezTerm" || os.Getenv("LC_TERMINAL") == "iTerm2":
+		return ImageITerm
+	case strings.Contains(termName, "sixel") || termName == "foot" || termName == "foot-extra" || termName == "mlterm":
+		return ImageSixel
+	default:
+		return ImageBlocks
+	}
+}
+
+// resize scales img to w×h pixels, averaging the source pixels behind each one.
+func resize(img image.Image, w, h int) *image.RGBA {
+	src := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := src.Min.Y + y*src.Dy()/h
+		y1 := max(y0+1, src.Min.Y+(y+1)*src.Dy()/h)
+		for x := 0; x < w; x++ {
+			x0 := src.Min.X + x*src.Dx()/w
+			x1 := max(x0+1, src.Min.X+(x+1)*src.Dx()/w)
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, b, a, n = r+cr, g+cg, b+cb, a+ca, n+1
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / n >> 8), uint8(g / n >> 8), uint8(b / n >> 8), uint8(a / n >> 8)})
+		}
+	}
+	return dst
+}
+
+// blocks draws the avatar with upper half blocks: each cell shows two pixels,
+// the top one as the foreground colour and the bottom one as the background.
+func (a *Avatar) blocks(cols, rows int) string {
+	img := resize(a.img, cols, rows*2)
+	lines := make([]string, rows)
+	for row := range lines {
+		var b strings.Builder
+		for x := 0; x < cols; x++ {
+			top, bottom := img.RGBAAt(x, row*2), img.RGBAAt(x, row*2+1)
+			fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀", top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+		}
+		b.WriteString("\x1b[0m")
+		lines[row] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// placeholder reserves the avatar's cells, starting with avatarMarker.
+func placeholder(cols, rows int) string {
+	lines := make([]string, rows)
+	for i := range lines {
+		lines[i] = strings.Repeat(" ", cols)
+	}
+	lines[0] = string(avatarMarker) + lines[0][1:]
+	return strings.Join(lines, "\n")
+}
+
+// kitty draws the avatar with the kitty graphics protocol, scaled to cols×rows
+// cells, without moving the cursor.
+func (a *Avatar) kitty(cols, rows int) string {
+	var buf bytes.Buffer
+	png.Encode(&buf, a.img)
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+	const chunk = 4096
+	var b strings.Builder
+	for i := 0; i < len(data); i += chunk {
+		end := min(i+chunk, len(data))
+		more := 0
+		if end < len(data) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, data[i:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, data[i:end])
+		}
+	}
+	return b.String()
+}
+
+// iterm draws the avatar as an iTerm2 inline image of cols×rows cells.
+func (a *Avatar) iterm(cols, rows int) string {
+	var buf bytes.Buffer
+	png.Encode(&buf, a.img)
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+		buf.Len(), cols, rows, base64.StdEncoding.EncodeToString(buf.Bytes()))
+}
+
+// sixel draws the avatar as a sixel image, quantised to a 6×6×6 colour cube.
+func (a *Avatar) sixel(cols, rows int) string {
+	w, h := cols*sixelCellWidth, rows*sixelCellHeight
+	img := resize(a.img, w, h)
+	level := func(v uint8) int { return (int(v)*5 + 127) / 255 }
+	index := make([]int, w*h)
+	used := map[int]bool{}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := img.RGBAAt(x, y)
+			i := level(c.R)*36 + level(c.G)*6 + level(c.B)
+			index[y*w+x] = i
+			used[i] = true
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\x1bPq\"1;1;%d;%d", w, h)
+	for i := range 216 {
+		if used[i] {
+			fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+		}
+	}
+	for band := 0; band < h; band += 6 {
+		first := true
+		for c := range 216 {
+			if !used[c] {
+				continue
+			}
+			var row strings.Builder
+			hit := false
+			prev, run := byte(0), 0
+			flush := func() {
+				switch {
+				case run > 3:
+					fmt.Fprintf(&row, "!%d%c", run, prev)
+				default:
+					row.WriteString(strings.Repeat(string(prev), run))
+				}
+			}
+			for x := 0; x < w; x++ {
+				bits := 0
+				for dy := 0; dy < 6 && band+dy < h; dy++ {
+					if index[(band+dy)*w+x] == c {
+						bits |= 1 << dy
+					}
+				}
+				if bits != 0 {
+					hit = true
+				}
+				ch := byte(63 + bits)
+				if ch == prev {
+					run++
+					continue
+				}
+				if run > 0 {
+					flush()
+				}
+				prev, run = ch, 1
+			}
+			if prev != '?' {
+				flush()
+			}
+			if !hit {
+				continue
+			}
+			if !first {
+				b.WriteString("$")
+			}
+			first = false
+			fmt.Fprintf(&b, "#%d%s", c, row.String())
+		}
+		b.WriteString("-")
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// image returns the escape sequence drawing the avatar with protocol.
+func (a *Avatar) image(protocol string, cols, rows int) string {
+	switch protocol {
+	case ImageKitty:
+		return a.kitty(cols, rows)
+	case ImageITerm:
+		return a.iterm(cols, rows)
+	case ImageSixel:
+		return a.sixel(cols, rows)
+	}
+	return ""
+}
+
+// overlayAvatar finds the placeholder in a rendered card and returns the card
+// without the marker plus the sequence that, printed right after the card,
+// draws the avatar over the placeholder and returns the cursor. ok is false
+// when there is no placeholder or the card is too tall for a terminal of
+// height rows to move back up to it.
+func overlayAvatar(card string, a *Avatar, protocol string, height int) (clean, draw string, ok bool) {
+	lines := strings.Split(card, "\n")
+	for i, line := range lines {
+		at := strings.IndexRune(line, avatarMarker)
+		if at < 0 {
+			continue
+		}
+		up := len(lines) - 1 - i
+		if up >= height {
+			return "", "", false
+		}
+		col := lipgloss.Width(line[:at])
+		clean = strings.Replace(card, string(avatarMarker), " ", 1)
+		var b strings.Builder
+		b.WriteString("\x1b7")
+		if up > 0 {
+			fmt.Fprintf(&b, "\x1b[%dA", up)
+		}
+		b.WriteString("\r")
+		if col > 0 {
+			fmt.Fprintf(&b, "\x1b[%dC", col)
+		}
+		b.WriteString(a.image(protocol, avatarCols, avatarRows))
+		b.WriteString("\x1b8")
+		return clean, b.String(), true
+	}
+	return "", "", false
+}
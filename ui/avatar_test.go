@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// testAvatar is red above blue, so the half blocks' colours can be checked.
+func testAvatar() *Avatar {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			c := color.RGBA{255, 0, 0, 255}
+			if y >= 4 {
+				c = color.RGBA{0, 0, 255, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return &Avatar{img: img}
+}
+
+func TestAvatarBlocks(t *testing.T) {
+	for _, size := range [][2]int{{avatarCols, avatarRows}, {4, 2}, {1, 1}} {
+		cols, rows := size[0], size[1]
+		lines := strings.Split(testAvatar().blocks(cols, rows), "\n")
+		if len(lines) != rows {
+			t.Fatalf("blocks(%d, %d): %d lines", cols, rows, len(lines))
+		}
+		for i, line := range lines {
+			if w := ansi.StringWidth(line); w != cols {
+				t.Errorf("blocks(%d, %d): line %d is %d cells wide", cols, rows, i, w)
+			}
+		}
+	}
+	lines := strings.Split(testAvatar().blocks(4, 2), "\n")
+	if !strings.HasPrefix(lines[0], "\x1b[38;2;255;0;0m\x1b[48;2;255;0;0m▀") || !strings.HasPrefix(lines[1], "\x1b[38;2;0;0;255m\x1b[48;2;0;0;255m▀") {
+		t.Errorf("expected red cells above blue ones, got %q", lines)
+	}
+}
+
+func TestAvatarProtocols(t *testing.T) {
+	a := testAvatar()
+	for _, tc := range []struct {
+		protocol, prefix, suffix string
+	}{
+		{ImageKitty, fmt.Sprintf("\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,", avatarCols, avatarRows), "\x1b\\"},
+		{ImageITerm, "\x1b]1337;File=inline=1;size=", "\a"},
+		{ImageSixel, fmt.Sprintf("\x1bPq\"1;1;%d;%d", avatarCols*sixelCellWidth, avatarRows*sixelCellHeight), "\x1b\\"},
+	} {
+		out := a.image(tc.protocol, avatarCols, avatarRows)
+		if !strings.HasPrefix(out, tc.prefix) || !strings.HasSuffix(out, tc.suffix) {
+			t.Errorf("%s: unexpected framing %q…%q", tc.protocol, out[:min(len(out), 40)], out[max(0, len(out)-4):])
+		}
+	}
+	if out := a.image(ImageBlocks, avatarCols, avatarRows); out != "" {
+		t.Errorf("blocks have no escape sequence, got %q", out)
+	}
+}
+
+func TestOverlayAvatar(t *testing.T) {
+	card := "top line\n" + lipgloss.JoinHorizontal(lipgloss.Top, "│ ", placeholder(avatarCols, avatarRows), "  name") + "\nbottom"
+	clean, draw, ok := overlayAvatar(card, testAvatar(), ImageKitty, 40)
+	if !ok {
+		t.Fatal("expected the placeholder to be found")
+	}
+	if strings.ContainsRune(clean, avatarMarker) {
+		t.Error("the marker was left in the card")
+	}
+	before, after := strings.Split(card, "\n"), strings.Split(clean, "\n")
+	if len(before) != len(after) {
+		t.Fatalf("line count changed from %d to %d", len(before), len(after))
+	}
+	for i := range before {
+		if lipgloss.Width(before[i]) != lipgloss.Width(after[i]) {
+			t.Errorf("line %d changed width: %q to %q", i, before[i], after[i])
+		}
+	}
+	// The placeholder starts on line 1 of 7 and 2 cells in.
+	if want := "\x1b7\x1b[5A\r\x1b[2C\x1b_G"; !strings.HasPrefix(draw, want) || !strings.HasSuffix(draw, "\x1b8") {
+		t.Errorf("expected the cursor moved up 5 and right 2, got %q", draw[:min(len(draw), 20)])
+	}
+
+	if _, _, ok := overlayAvatar(card, testAvatar(), ImageKitty, 5); ok {
+		t.Error("expected a card taller than the terminal to be refused")
+	}
+	if _, _, ok := overlayAvatar("no avatar here", testAvatar(), ImageKitty, 40); ok {
+		t.Error("expected a card without a placeholder to be refused")
+	}
+}
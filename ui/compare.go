@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"ghprofile/github"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+// compareRow is one metric of the comparison table. Rows with a nil value have
+// no leader.
+type compareRow struct {
+	label  string
+	value  func(s github.Summary) float64
+	format func(s github.Summary) string
+}
+
+var compareRows = []compareRow{
+	{"Followers", func(s github.Summary) float64 { return float64(s.Followers) }, func(s github.Summary) string { return fmt.Sprintf("%d", s.Followers) }},
+	{"Public repos", func(s github.Summary) float64 { return float64(s.PublicRepos) }, func(s github.Summary) string { return fmt.Sprintf("%d", s.PublicRepos) }},
+	{"Total stars", func(s github.Summary) float64 { return float64(s.TotalStars) }, func(s github.Summary) string { return fmt.Sprintf("%d", s.TotalStars) }},
+	{"Total forks", func(s github.Summary) float64 { return float64(s.TotalForks) }, func(s github.Summary) string { return fmt.Sprintf("%d", s.TotalForks) }},
+	{"Avg stars/repo", func(s github.Summary) float64 { return s.AvgStars }, func(s github.Summary) string { return fmt.Sprintf("%.2f", s.AvgStars) }},
+	{"Top languages", nil, func(s github.Summary) string { return strings.Join(s.TopLanguages, ", ") }},
+	{"Account age", func(s github.Summary) float64 { return float64(s.AccountAgeDays) }, func(s github.Summary) string {
+		if s.JoinedAt == "" {
+			return "?"
+		}
+		return formatDays(s.AccountAgeDays)
+	}},
+}
+
+// leaders returns, per row, which columns hold the highest value. Nobody leads a
+// row where everyone is tied.
+func leaders(row compareRow, users []github.Summary) map[int]bool {
+	lead := map[int]bool{}
+	if row.value == nil || len(users) < 2 {
+		return lead
+	}
+	best := row.value(users[0])
+	tied := true
+	for _, u := range users[1:] {
+		v := row.value(u)
+		if v != best {
+			tied = false
+		}
+		if v > best {
+			best = v
+		}
+	}
+	if tied {
+		return lead
+	}
+	for i, u := range users {
+		if row.value(u) == best {
+			lead[i] = true
+		}
+	}
+	return lead
+}
+
+// RenderCompare renders users side by side, one column per user, highlighting
+// the leader of each row. Without styles leaders are marked with an asterisk.
+func RenderCompare(users []github.Summary, noStyle bool) string {
+	headers := []string{""}
+	for _, u := range users {
+		headers = append(headers, u.Login)
+	}
+	var rows [][]string
+	var leads []map[int]bool
+	for _, row := range compareRows {
+		cells := []string{row.label}
+		lead := leaders(row, users)
+		for i, u := range users {
+			cell := row.format(u)
+			if noStyle && lead[i] {
+				cell += " *"
+			}
+			cells = append(cells, cell)
+		}
+		rows = append(rows, cells)
+		leads = append(leads, lead)
+	}
+
+	if noStyle {
+		widths := make([]int, len(headers))
+		for _, r := range append([][]string{headers}, rows...) {
+			for i, c := range r {
+				if w := lipgloss.Width(c); w > widths[i] {
+					widths[i] = w
+				}
+			}
+		}
+		var b strings.Builder
+		for _, r := range append([][]string{headers}, rows...) {
+			for i, c := range r {
+				b.WriteString(fmt.Sprintf("%-*s", widths[i]+2, c))
+			}
+			b.WriteString("\n")
+		}
+		return b.String()
+	}
+
+	t := table.New().
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(accentBlue)).
+		Headers(headers...).
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			s := lipgloss.NewStyle().Padding(0, 1)
+			switch {
+			case row == table.HeaderRow:
+				return s.Inherit(TitleStyle)
+			case col == 0:
+				return s.Inherit(Accent)
+			case leads[row][col-1]:
+				return s.Inherit(ValueStyle)
+			default:
+				return s.Foreground(brightFg)
+			}
+		})
+	return t.Render() + "\n"
+}
+
+// RenderCompareMarkdown renders the comparison as a Markdown table with leaders in bold.
+func RenderCompareMarkdown(users []github.Summary) string {
+	var b strings.Builder
+	b.WriteString("|")
+	for _, u := range users {
+		fmt.Fprintf(&b, " | [%s](https://github.com/%s)", u.Login, u.Login)
+	}
+	b.WriteString(" |\n|---" + strings.Repeat("|---:", len(users)) + "|\n")
+	for _, row := range compareRows {
+		lead := leaders(row, users)
+		b.WriteString("| " + row.label)
+		for i, u := range users {
+			cell := mdEscape(row.format(u))
+			if lead[i] {
+				cell = "**" + cell + "**"
+			}
+			b.WriteString(" | " + cell)
+		}
+		b.WriteString(" |\n")
+	}
+	return b.String()
+}
+
+// RenderCompareJSON renders the summaries and, per metric, the logins leading it.
+func RenderCompareJSON(users []github.Summary) ([]byte, error) {
+	out := struct {
+		Users   []github.Summary    `json:"users"`
+		Leaders map[string][]string `json:"leaders"`
+	}{Users: users, Leaders: map[string][]string{}}
+	for _, row := range compareRows {
+		lead := leaders(row, users)
+		for i, u := range users {
+			if lead[i] {
+				out.Leaders[row.label] = append(out.Leaders[row.label], u.Login)
+			}
+		}
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// formatDays describes a number of days in its largest whole unit.
+func formatDays(days int) string {
+	switch {
+	case days >= 365:
+		return plural(days/365, "year")
+	case days >= 30:
+		return plural(days/30, "month")
+	default:
+		return plural(days, "day")
+	}
+}
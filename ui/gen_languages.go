@@ -0,0 +1,156 @@
+//go:build ignore
+
+// gen_languages reads Linguist's languages.yml and writes languages_gen.go. It
+// understands just the shape of that file (top-level language names with
+// indented fields and lists), so it needs no YAML library. With -fetch it
+// first downloads the upstream file over -in.
+//
+//	go run gen_languages.go [-fetch] [-in linguist/languages.yml] [-out languages_gen.go]
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// upstream is Linguist's language table.
+const upstream = "https://raw.githubusercontent.com/github-linguist/linguist/main/lib/linguist/languages.yml"
+
+type language struct {
+	name, typ, color, group string
+	// extension is the first of the language's extensions, its primary one.
+	extension string
+	aliases   []string
+}
+
+func main() {
+	in := flag.String("in", "linguist/languages.yml", "Linguist languages.yml to read")
+	out := flag.String("out", "languages_gen.go", "Go file to write")
+	fetchUpstream := flag.Bool("fetch", false, "Download the upstream languages.yml to -in first")
+	flag.Parse()
+
+	if *fetchUpstream {
+		if err := fetch(upstream, *in); err != nil {
+			log.Fatal(err)
+		}
+	}
+	langs, err := parse(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(langs, *in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// fetch downloads url to path.
+func fetch(url, path string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, body, 0o644)
+}
+
+// parse reads the languages, their type, colour, group, primary extension and
+// aliases.
+func parse(path string) ([]language, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var langs []language
+	var cur *language
+	field := ""
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || trimmed == "---" || strings.HasPrefix(trimmed, "#"):
+		case !strings.HasPrefix(line, " "):
+			if !strings.HasSuffix(trimmed, ":") {
+				return nil, fmt.Errorf("%s:%d: expected a language name", path, n)
+			}
+			langs = append(langs, language{name: unquote(strings.TrimSuffix(trimmed, ":"))})
+			cur, field = &langs[len(langs)-1], ""
+		case cur == nil:
+			return nil, fmt.Errorf("%s:%d: field outside a language", path, n)
+		case strings.HasPrefix(trimmed, "- "):
+			item := unquote(strings.TrimPrefix(trimmed, "- "))
+			switch {
+			case field == "aliases":
+				cur.aliases = append(cur.aliases, item)
+			case field == "extensions" && cur.extension == "":
+				cur.extension = item
+			}
+		default:
+			key, value, _ := strings.Cut(trimmed, ":")
+			field, value = key, unquote(strings.TrimSpace(value))
+			switch key {
+			case "type":
+				cur.typ = value
+			case "color":
+				cur.color = value
+			case "group":
+				cur.group = value
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(langs, func(i, j int) bool { return langs[i].name < langs[j].name })
+	return langs, nil
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func generate(langs []language, source string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gen_languages.go from %s; DO NOT EDIT.\n\npackage ui\n\n", source)
+	b.WriteString("var languages = []Language{\n")
+	for _, l := range langs {
+		fmt.Fprintf(&b, "\t{Name: %q, Type: %q, Color: %q", l.name, l.typ, l.color)
+		if l.group != "" {
+			fmt.Fprintf(&b, ", Group: %q", l.group)
+		}
+		if l.extension != "" {
+			fmt.Fprintf(&b, ", Extension: %q", l.extension)
+		}
+		if len(l.aliases) > 0 {
+			fmt.Fprintf(&b, ", Aliases: %#v", l.aliases)
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n")
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ghprofile/github"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// heatLevels maps GitHub's contribution levels to an index into heatColors.
+var heatLevels = map[string]int{
+	github.LevelNone:           0,
+	github.LevelFirstQuartile:  1,
+	github.LevelSecondQuartile: 2,
+	github.LevelThirdQuartile:  3,
+	github.LevelFourthQuartile: 4,
+}
+
+// plainHeat is used instead of colour when styles are disabled.
+var plainHeat = []string{"·", "░", "▒", "▓", "█"}
+
+// RenderHeatmap draws the contribution calendar as a grid with one column per week
+// and one row per weekday, with month names above the columns where a month starts.
+func RenderHeatmap(cal *github.ContributionCalendar, noStyle bool) string {
+	if cal == nil || len(cal.Weeks) == 0 {
+		return ""
+	}
+	const labelWidth = 4
+	var b strings.Builder
+
+	months := []rune(strings.Repeat(" ", len(cal.Weeks)+3))
+	lastMonth := time.Month(0)
+	for col, w := range cal.Weeks {
+		if len(w.ContributionDays) == 0 {
+			continue
+		}
+		d, err := time.Parse("2006-01-02", w.ContributionDays[0].Date)
+		if err != nil || d.Month() == lastMonth {
+			continue
+		}
+		lastMonth = d.Month()
+		// Skip a partial leading week so its label doesn't collide with the next month.
+		if col == 0 && d.Day() > 7 {
+			continue
+		}
+		copy(months[col:], []rune(d.Month().String()[:3]))
+	}
+	b.WriteString(strings.Repeat(" ", labelWidth) + strings.TrimRight(string(months), " ") + "\n")
+
+	dayLabels := []string{"", "Mon", "", "Wed", "", "Fri", ""}
+	for wd := 0; wd < 7; wd++ {
+		b.WriteString(fmt.Sprintf("%-*s", labelWidth, dayLabels[wd]))
+		for _, w := range cal.Weeks {
+			cell := " "
+			for _, d := range w.ContributionDays {
+				if d.Weekday == wd {
+					cell = heatCell(d.ContributionLevel, noStyle)
+					break
+				}
+			}
+			b.WriteString(cell)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(strings.Repeat(" ", labelWidth) + "Less ")
+	for _, lvl := range []string{github.LevelNone, github.LevelFirstQuartile, github.LevelSecondQuartile, github.LevelThirdQuartile, github.LevelFourthQuartile} {
+		b.WriteString(heatCell(lvl, noStyle))
+	}
+	b.WriteString(" More\n")
+	return b.String()
+}
+
+func heatCell(level string, noStyle bool) string {
+	i := heatLevels[level]
+	if noStyle {
+		return plainHeat[i]
+	}
+	return lipgloss.NewStyle().Foreground(heatColors[i]).Render("■")
+}
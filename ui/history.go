@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"ghprofile/github"
+)
+
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values as one block character each, scaled between their
+// minimum and maximum.
+func Sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	out := make([]rune, len(values))
+	for i, v := range values {
+		idx := 0
+		if hi > lo {
+			idx = (v - lo) * (len(sparkTicks) - 1) / (hi - lo)
+		}
+		out[i] = sparkTicks[idx]
+	}
+	return string(out)
+}
+
+// maxSparkWidth caps how many of the latest snapshots a sparkline shows.
+const maxSparkWidth = 60
+
+// RenderHistory shows a sparkline per metric across the snapshots, with the
+// latest value and the change over the whole range.
+func RenderHistory(user string, snaps []github.Snapshot, noStyle bool) string {
+	if len(snaps) > maxSparkWidth {
+		snaps = snaps[len(snaps)-maxSparkWidth:]
+	}
+	first, last := snaps[0], snaps[len(snaps)-1]
+	var b strings.Builder
+	header := fmt.Sprintf("History for %s: %s from %s to %s", user, plural(len(snaps), "snapshot"), first.Time.Format("2006-01-02"), last.Time.Format("2006-01-02"))
+	if noStyle {
+		b.WriteString(header + "\n\n")
+	} else {
+		b.WriteString(TitleStyle.Render(header) + "\n\n")
+	}
+	metrics := []struct {
+		label string
+		value func(s github.Snapshot) int
+	}{
+		{"Followers", func(s github.Snapshot) int { return s.Followers }},
+		{"Stars", func(s github.Snapshot) int { return s.TotalStars }},
+		{"Forks", func(s github.Snapshot) int { return s.TotalForks }},
+		{"Public repos", func(s github.Snapshot) int { return s.PublicRepos }},
+	}
+	for _, m := range metrics {
+		values := make([]int, len(snaps))
+		for i, s := range snaps {
+			values[i] = m.value(s)
+		}
+		line := Sparkline(values)
+		now, delta := values[len(values)-1], values[len(values)-1]-values[0]
+		if noStyle {
+			b.WriteString(fmt.Sprintf("%-13s %s %d (%s)\n", m.label, line, now, signed(delta)))
+			continue
+		}
+		b.WriteString(fmt.Sprintf("%s %s %s %s\n", Accent.Render(fmt.Sprintf("%-13s", m.label)), IconStyle.Render(line), ValueStyle.Render(fmt.Sprintf("%d", now)), deltaStyle(delta).Render("("+signed(delta)+")")))
+	}
+	return b.String()
+}
+
+// maxDiffRepos caps how many per-repo changes RenderDiff lists.
+const maxDiffRepos = 10
+
+// RenderDiff shows follower, star and fork changes plus new, removed and changed repos.
+func RenderDiff(user string, d github.SnapshotDiff, noStyle bool) string {
+	var b strings.Builder
+	header := fmt.Sprintf("Changes for %s between %s and %s", user, d.From.Format("2006-01-02"), d.To.Format("2006-01-02"))
+	title := func(s string) string {
+		if noStyle {
+			return s
+		}
+		return Subtle.Render(s)
+	}
+	if noStyle {
+		b.WriteString(header + "\n\n")
+	} else {
+		b.WriteString(TitleStyle.Render(header) + "\n\n")
+	}
+	for _, row := range []struct {
+		label        string
+		value, delta int
+	}{
+		{"Followers:", d.Followers, d.FollowersDelta},
+		{"Stars:", d.Stars, d.StarsDelta},
+		{"Forks:", d.Forks, d.ForksDelta},
+	} {
+		if noStyle {
+			b.WriteString(fmt.Sprintf("%-11s %d (%s)\n", row.label, row.value, signed(row.delta)))
+			continue
+		}
+		b.WriteString(fmt.Sprintf("%s %s %s\n", Accent.Render(fmt.Sprintf("%-11s", row.label)), ValueStyle.Render(fmt.Sprintf("%d", row.value)), deltaStyle(row.delta).Render("("+signed(row.delta)+")")))
+	}
+	if len(d.NewRepos) > 0 {
+		b.WriteString("\n" + title("New repos:") + "\n")
+		for _, r := range d.NewRepos {
+			b.WriteString("  + " + r + "\n")
+		}
+	}
+	if len(d.RemovedRepos) > 0 {
+		b.WriteString("\n" + title("Removed repos:") + "\n")
+		for _, r := range d.RemovedRepos {
+			b.WriteString("  - " + r + "\n")
+		}
+	}
+	if len(d.RepoDeltas) > 0 {
+		b.WriteString("\n" + title("Repo changes:") + "\n")
+		for i, r := range d.RepoDeltas {
+			if i == maxDiffRepos {
+				b.WriteString(fmt.Sprintf("  … and %d more\n", len(d.RepoDeltas)-i))
+				break
+			}
+			stars := fmt.Sprintf("★ %d (%s)", r.Stars, signed(r.StarsDelta))
+			if !noStyle {
+				stars = deltaStyle(r.StarsDelta).Render(stars)
+			}
+			b.WriteString(fmt.Sprintf("  %s %s  forks %s\n", r.FullName, stars, signed(r.ForksDelta)))
+		}
+	}
+	return b.String()
+}
+
+// signed formats n with an explicit sign, e.g. "+3", "-1" or "±0".
+func signed(n int) string {
+	switch {
+	case n > 0:
+		return fmt.Sprintf("+%d", n)
+	case n < 0:
+		return fmt.Sprintf("%d", n)
+	default:
+		return "±0"
+	}
+}
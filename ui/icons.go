@@ -1,13 +1,205 @@
 package ui
 
-var (
-	IconStar      = ""
-	IconFork      = ""
-	IconLang      = "󰅩"
-	IconUser      = ""
-	IconRepo      = ""
-	IconLink      = ""
-	IconFollowers = ""
-	IconFollowing = "󰭓"
-	IconGist      = ""
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 )
+
+// Icon sets selectable with --icons.
+const (
+	IconsNerd  = "nerd"
+	IconsEmoji = "emoji"
+	IconsASCII = "ascii"
+	IconsNone  = "none"
+)
+
+// IconSets lists the icon sets in the order they are offered.
+var IconSets = []string{IconsNerd, IconsEmoji, IconsASCII, IconsNone}
+
+// iconSet holds one icon per symbol the views print, plus the language icons.
+type iconSet struct {
+	star, fork, lang, user, repo, link, followers, following, gist, activity,
+	commit, pr, issue, tag, lock, company, location, twitter, email, hireable,
+	joined, stargazers string
+	// langOf picks the set's icon from a language's languageIcons entry; sets
+	// without one show the language's extension instead.
+	langOf func(langIcons) string
+	// typeLang holds icons for languages without their own, by Linguist type.
+	typeLang map[string]string
+	// otherLang is shown for languages missing from the table, noLang for
+	// repos without a language.
+	otherLang, noLang string
+}
+
+var iconSets = map[string]iconSet{
+	IconsNerd: {
+		star: "", fork: "", lang: "󰅩", user: "", repo: "",
+		link: "", followers: "", following: "󰭓", gist: "",
+		activity: "", commit: "", pr: "", issue: "", tag: "",
+		lock: "", company: "", location: "", twitter: "",
+		email: "", hireable: "", joined: "", stargazers: "★",
+		langOf: func(l langIcons) string { return l.nerd },
+		typeLang: map[string]string{
+			"programming": "", "markup": "", "data": "", "prose": "",
+		},
+		otherLang: "λ", noLang: "(unknown)",
+	},
+	IconsEmoji: {
+		star: "⭐", fork: "🍴", lang: "💬", user: "👤", repo: "📦", link: "🔗",
+		followers: "👥", following: "👣", gist: "📝", activity: "📈", commit: "🔨",
+		pr: "🔀", issue: "🐛", tag: "🔖", lock: "🔒", company: "🏢", location: "📍",
+		twitter: "🐤", email: "📧", hireable: "💼", joined: "📅", stargazers: "⭐",
+		langOf: func(l langIcons) string { return l.emoji },
+		typeLang: map[string]string{
+			"programming": "💻", "markup": "📰", "data": "💾", "prose": "📖",
+		},
+		otherLang: "🧩", noLang: "❔",
+	},
+	IconsASCII: {
+		star: "*", fork: "Y", lang: "#", user: "@", repo: "R", link: "~",
+		followers: "+", following: "-", gist: "G", activity: "%", commit: "o",
+		pr: ">", issue: "!", tag: "T", lock: "$", company: "C", location: "^",
+		twitter: "t", email: "e", hireable: "H", joined: "J", stargazers: "*",
+		otherLang: "..", noLang: "??",
+	},
+}
+
+// icons returns the icon set named by o.Icons. "none" uses the ASCII set for
+// the few symbols printed even without icons; the rest are hidden by
+// ShowIcons. An empty or unknown name means nerd.
+func (o Options) icons() iconSet {
+	name := o.Icons
+	if name == IconsNone {
+		name = IconsASCII
+	}
+	if s, ok := iconSets[name]; ok {
+		return s
+	}
+	return iconSets[IconsNerd]
+}
+
+// langIcon returns the icon for a language name or alias: its own, its
+// group's, or one for its type. The ASCII set shows its extension.
+func (s iconSet) langIcon(lang string) string {
+	if lang == "" {
+		return s.noLang
+	}
+	l, ok := LookupLanguage(lang)
+	if !ok {
+		return s.otherLang
+	}
+	if s.langOf == nil {
+		return abbreviation(l)
+	}
+	for _, name := range []string{l.Name, l.Group} {
+		if icons, ok := languageIcons[name]; ok && s.langOf(icons) != "" {
+			return s.langOf(icons)
+		}
+	}
+	if icon := s.typeLang[l.Type]; icon != "" {
+		return icon
+	}
+	return s.otherLang
+}
+
+// abbreviation is an ASCII icon for l: up to two letters of its primary
+// extension, or of its name when it has none.
+func abbreviation(l Language) string {
+	abbr := strings.TrimPrefix(l.Extension, ".")
+	if abbr == "" {
+		abbr = strings.ToLower(strings.ReplaceAll(l.Name, " ", ""))
+	}
+	if r := []rune(abbr); len(r) > 2 {
+		abbr = string(r[:2])
+	}
+	return abbr
+}
+
+// DetectIconSet guesses which icon set the terminal can show. $GHPROFILE_ICONS
+// wins when set. Output that isn't text on a terminal gets ASCII without
+// looking further. Terminals that bundle Nerd Font symbols (kitty, WezTerm,
+// Ghostty), or a Nerd Font installed among the user's fonts, mean nerd;
+// otherwise a UTF-8 locale gets emoji and anything else ASCII.
+func DetectIconSet(terminal bool) string {
+	if env := strings.ToLower(os.Getenv("GHPROFILE_ICONS")); slices.Contains(IconSets, env) {
+		return env
+	}
+	if !terminal {
+		return IconsASCII
+	}
+	termName, program := os.Getenv("TERM"), strings.ToLower(os.Getenv("TERM_PROGRAM"))
+	if termName == "linux" || termName == "dumb" {
+		return IconsASCII
+	}
+	if os.Getenv("NERD_FONT") != "" || termName == "xterm-kitty" || program == "wezterm" || program == "ghostty" {
+		return IconsNerd
+	}
+	if nerdFontInstalled() {
+		return IconsNerd
+	}
+	if utf8Locale() {
+		return IconsEmoji
+	}
+	return IconsASCII
+}
+
+// nerdFontInstalled reports whether a font file with "Nerd" in its name is in
+// one of the usual font directories. That the font is installed suggests the
+// terminal is set up to use it.
+func nerdFontInstalled() bool {
+	var dirs []string
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".local", "share", "fonts"), filepath.Join(home, ".fonts"), filepath.Join(home, "Library", "Fonts"))
+	}
+	if data := os.Getenv("XDG_DATA_HOME"); data != "" {
+		dirs = append(dirs, filepath.Join(data, "fonts"))
+	}
+	dirs = append(dirs, "/usr/share/fonts", "/usr/local/share/fonts", "/Library/Fonts")
+	for _, dir := range dirs {
+		if nerdFontIn(dir, maxFontDepth) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxFontDepth is how many directory levels below a font directory
+// nerdFontIn looks. Font packages go one or two levels deep, e.g.
+// /usr/share/fonts/truetype/<family>/.
+const maxFontDepth = 2
+
+// nerdFontIn reports whether dir, or a directory at most depth levels below
+// it, holds a file with "Nerd" in its name.
+func nerdFontIn(dir string, depth int) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if !e.IsDir() && strings.Contains(strings.ToLower(e.Name()), "nerd") {
+			return true
+		}
+	}
+	if depth == 0 {
+		return false
+	}
+	for _, e := range entries {
+		if e.IsDir() && nerdFontIn(filepath.Join(dir, e.Name()), depth-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// utf8Locale reports whether the locale environment asks for UTF-8.
+func utf8Locale() bool {
+	for _, env := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIconSetsMapEveryLanguage(t *testing.T) {
+	for name := range languageIcons {
+		if l, ok := LookupLanguage(name); !ok || l.Name != name {
+			t.Errorf("icon for %q, which is not a Linguist language name", name)
+		}
+	}
+	for _, name := range IconSets {
+		set := Options{Icons: name}.icons()
+		for _, l := range languages {
+			if icon := set.langIcon(l.Name); icon == "" || icon == set.otherLang {
+				t.Errorf("icon set %q has no icon for %s", name, l.Name)
+			}
+		}
+		if set.star == "" || set.joined == "" || set.stargazers == "" || set.langIcon("") == "" || set.langIcon("Klingon") == "" {
+			t.Errorf("icon set %q leaves icons empty", name)
+		}
+	}
+	if got, want := (Options{Icons: "wingdings"}).icons().star, iconSets[IconsNerd].star; got != want {
+		t.Fatalf("unknown icon set gave star %q, want the nerd %q", got, want)
+	}
+}
+
+func TestLangIconFallbacks(t *testing.T) {
+	nerd, ascii := iconSets[IconsNerd], iconSets[IconsASCII]
+	for _, tc := range []struct {
+		set        iconSet
+		lang, want string
+	}{
+		{nerd, "TSX", languageIcons["TypeScript"].nerd},
+		{nerd, "SQL", nerd.typeLang["data"]},
+		{nerd, "COBOL", nerd.typeLang["programming"]},
+		{ascii, "Go", "go"},
+		{ascii, "Objective-C++", "mm"},
+		{ascii, "Dockerfile", "do"},
+	} {
+		if got := tc.set.langIcon(tc.lang); got != tc.want {
+			t.Errorf("langIcon(%q) = %q, want %q", tc.lang, got, tc.want)
+		}
+	}
+}
+
+func TestNerdFontInStopsAtDepth(t *testing.T) {
+	dir := t.TempDir()
+	deep := filepath.Join(dir, "a", "b", "c")
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(deep, "HackNerdFont-Regular.ttf"), nil, 0o644)
+	if nerdFontIn(dir, maxFontDepth) {
+		t.Fatal("found a font deeper than maxFontDepth")
+	}
+	if !nerdFontIn(filepath.Join(dir, "a"), maxFontDepth) {
+		t.Fatal("missed a font within maxFontDepth")
+	}
+}
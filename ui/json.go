@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"ghprofile/github"
+)
+
+// PrintJSON writes the profile and its repos to stdout as indented JSON.
+func PrintJSON(p *github.Profile, repos []github.Repo) error {
+	b, err := RenderJSON(p, repos)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+// RenderJSON returns the JSON PrintJSON prints, without the trailing newline.
+func RenderJSON(p *github.Profile, repos []github.Repo) ([]byte, error) {
+	out := struct {
+		Profile *github.Profile `json:"profile"`
+		Repos   []github.Repo   `json:"repos"`
+	}{p, repos}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// RenderRepoJSON returns a repo card's data as indented JSON.
+func RenderRepoJSON(d *github.RepoDetail) ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
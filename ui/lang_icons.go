@@ -1,37 +1,47 @@
 package ui
 
-import "strings"
-
-var LangIcons = map[string]string{
-	"typescript": "",
-	"javascript": "",
-	"go":         "",
-	"python":     "",
-	"rust":       "",
-	"c++":        "󰙲",
-	"c#":         "",
-	"php":        "",
-	"java":       "",
-	"zig":        "",
-	"shell":      "",
-	"vue":        "",
-	"odin":       "◎",
-	"react":      "",
-	"ruby":       "",
-	"swift":      "",
-	"kotlin":     "",
-	"dart":       "",
-	"elixir":     "",
-	"haskell":    "",
+// langIcons are a language's icons in the nerd and emoji sets. ASCII icons
+// are the language's extension, see abbreviation.
+type langIcons struct {
+	nerd, emoji string
 }
 
-func GetLangIcon(lang string) string {
-	if lang == "" {
-		return "(unknown)"
-	}
-	k := strings.ToLower(lang)
-	if v, ok := LangIcons[k]; ok {
-		return v
-	}
-	return "λ"
+// languageIcons maps Linguist language names to their icons. Languages not
+// listed take their group's icons; failing that, iconSet.langIcon derives one
+// for its type.
+var languageIcons = map[string]langIcons{
+	"TypeScript": {"", "🔷"},
+	"JavaScript": {"", "🟨"},
+	"Go":         {"", "🐹"},
+	"Python":     {"", "🐍"},
+	"Rust":       {"", "🦀"},
+	"C++":        {"󰙲", "🔧"},
+	"C#":         {"", "🎼"},
+	"PHP":        {"", "🐘"},
+	"Java":       {"", "☕"},
+	"Zig":        {"", "⚡"},
+	"Shell":      {"", "🐚"},
+	"Vue":        {"", "💚"},
+	"Odin":       {"◎", "🧿"},
+	"Ruby":       {"", "💎"},
+	"Swift":      {"", "🐦"},
+	"Kotlin":     {"", "🟪"},
+	"Dart":       {"", "🎯"},
+	"Elixir":     {"", "🧪"},
+	"Haskell":    {"", "🟣"},
+	"C":          {"", "🔩"},
+	"HTML":       {"", "🌐"},
+	"CSS":        {"", "🎨"},
+	"SCSS":       {"", "💅"},
+	"Lua":        {"", "🌙"},
+	"Markdown":   {"", "📄"},
+	"Dockerfile": {"", "🐳"},
+	"Scala":      {"", "🔺"},
+	"Perl":       {"", "🐪"},
+	"Clojure":    {"", "🍀"},
+	"Erlang":     {"", "📞"},
+	"Elm":        {"", "🌳"},
+	"Julia":      {"", "🔮"},
+	"Vim Script": {"", "📗"},
+	"Nix":        {"", "🧊"},
 }
@@ -0,0 +1,64 @@
+package ui
+
+//go:generate go run gen_languages.go
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Language is an entry of GitHub Linguist's language table.
+type Language struct {
+	// Name is Linguist's canonical name, as the API reports it.
+	Name string
+	// Type is programming, markup, data or prose.
+	Type string
+	// Color is the colour GitHub shows the language in, e.g. "#00ADD8".
+	Color string
+	// Group is the language Linguist counts this one as, e.g. TypeScript for
+	// TSX, if any.
+	Group string
+	// Extension is the primary file extension, e.g. ".go".
+	Extension string
+	Aliases   []string
+}
+
+var (
+	languageIndexOnce sync.Once
+	languageIndex     map[string]*Language
+)
+
+// LookupLanguage finds a language by name or alias, ignoring case.
+func LookupLanguage(name string) (Language, bool) {
+	languageIndexOnce.Do(func() {
+		languageIndex = make(map[string]*Language, len(languages)*2)
+		for i := range languages {
+			l := &languages[i]
+			languageIndex[strings.ToLower(l.Name)] = l
+			for _, alias := range l.Aliases {
+				languageIndex[strings.ToLower(alias)] = l
+			}
+		}
+	})
+	l, ok := languageIndex[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Language{}, false
+	}
+	return *l, true
+}
+
+// langColor returns the language's GitHub colour. Languages without one take
+// a colour from langPalette by their rank.
+func langColor(name string, rank int) lipgloss.Color {
+	if l, ok := LookupLanguage(name); ok && l.Color != "" {
+		return lipgloss.Color(l.Color)
+	}
+	return langPalette[rank%len(langPalette)]
+}
+
+// langStyle renders a language name in its colour.
+func langStyle(name string, rank int) lipgloss.Style {
+	return lipgloss.NewStyle().Bold(true).Foreground(langColor(name, rank))
+}
@@ -0,0 +1,160 @@
+// Code generated by gen_languages.go from linguist/languages.yml; DO NOT EDIT.
+
+package ui
+
+var languages = []Language{
+	{Name: "ActionScript", Type: "programming", Color: "#882B0F", Extension: ".as", Aliases: []string{"actionscript 3", "actionscript3", "as3"}},
+	{Name: "Ada", Type: "programming", Color: "#02f88c", Extension: ".adb", Aliases: []string{"ada95", "ada2005"}},
+	{Name: "Apex", Type: "programming", Color: "#1797c0", Extension: ".cls"},
+	{Name: "AppleScript", Type: "programming", Color: "#101F1F", Extension: ".applescript", Aliases: []string{"osascript"}},
+	{Name: "AsciiDoc", Type: "prose", Color: "#73a0c5", Extension: ".asciidoc"},
+	{Name: "Assembly", Type: "programming", Color: "#6E4C13", Extension: ".asm", Aliases: []string{"asm", "nasm"}},
+	{Name: "Astro", Type: "markup", Color: "#ff5a03", Extension: ".astro"},
+	{Name: "AutoHotkey", Type: "programming", Color: "#6594b9", Extension: ".ahk", Aliases: []string{"ahk"}},
+	{Name: "Awk", Type: "programming", Color: "#c30e9b", Extension: ".awk"},
+	{Name: "Ballerina", Type: "programming", Color: "#FF5000", Extension: ".bal"},
+	{Name: "Batchfile", Type: "programming", Color: "#C1F12E", Extension: ".bat", Aliases: []string{"bat", "batch", "dosbatch", "winbatch"}},
+	{Name: "Bicep", Type: "programming", Color: "#519aba", Extension: ".bicep"},
+	{Name: "Blade", Type: "markup", Color: "#f7523f", Extension: ".blade"},
+	{Name: "C", Type: "programming", Color: "#555555", Extension: ".c"},
+	{Name: "C#", Type: "programming", Color: "#178600", Extension: ".cs", Aliases: []string{"csharp", "cake", "cakescript"}},
+	{Name: "C++", Type: "programming", Color: "#f34b7d", Extension: ".cpp", Aliases: []string{"cpp"}},
+	{Name: "CMake", Type: "programming", Color: "#DA3434", Extension: ".cmake"},
+	{Name: "COBOL", Type: "programming", Color: "", Extension: ".cob"},
+	{Name: "CSS", Type: "markup", Color: "#563d7c", Extension: ".css"},
+	{Name: "CSV", Type: "data", Color: "#237346", Extension: ".csv"},
+	{Name: "Cairo", Type: "programming", Color: "#ff4a48", Extension: ".cairo"},
+	{Name: "Cap'n Proto", Type: "programming", Color: "#c42727", Extension: ".capnp"},
+	{Name: "Clojure", Type: "programming", Color: "#db5855", Extension: ".clj"},
+	{Name: "CoffeeScript", Type: "programming", Color: "#244776", Extension: ".coffee", Aliases: []string{"coffee", "coffee-script"}},
+	{Name: "Common Lisp", Type: "programming", Color: "#3fb68b", Extension: ".lisp", Aliases: []string{"lisp"}},
+	{Name: "Crystal", Type: "programming", Color: "#000100", Extension: ".cr"},
+	{Name: "Cuda", Type: "programming", Color: "#3A4E3A", Extension: ".cu"},
+	{Name: "Cython", Type: "programming", Color: "#fedf5b", Extension: ".pyx", Aliases: []string{"pyrex"}},
+	{Name: "D", Type: "programming", Color: "#ba595e", Extension: ".d", Aliases: []string{"Dlang"}},
+	{Name: "Dart", Type: "programming", Color: "#00B4AB", Extension: ".dart"},
+	{Name: "Dhall", Type: "programming", Color: "#dfafff", Extension: ".dhall"},
+	{Name: "Dockerfile", Type: "programming", Color: "#384d54", Extension: ".dockerfile", Aliases: []string{"Containerfile"}},
+	{Name: "EJS", Type: "markup", Color: "#a91e50", Extension: ".ejs"},
+	{Name: "Eiffel", Type: "programming", Color: "#4d6977", Extension: ".e"},
+	{Name: "Elixir", Type: "programming", Color: "#6e4a7e", Extension: ".ex"},
+	{Name: "Elm", Type: "programming", Color: "#60B5CC", Extension: ".elm"},
+	{Name: "Emacs Lisp", Type: "programming", Color: "#c065db", Extension: ".el", Aliases: []string{"elisp", "emacs"}},
+	{Name: "Erlang", Type: "programming", Color: "#B83998", Extension: ".erl"},
+	{Name: "F#", Type: "programming", Color: "#b845fc", Extension: ".fs", Aliases: []string{"fsharp"}},
+	{Name: "Fennel", Type: "programming", Color: "#fff3d7", Extension: ".fnl"},
+	{Name: "Forth", Type: "programming", Color: "#341708", Extension: ".fth"},
+	{Name: "Fortran", Type: "programming", Color: "#4d41b1", Extension: ".f"},
+	{Name: "GDScript", Type: "programming", Color: "#355570", Extension: ".gd"},
+	{Name: "GLSL", Type: "programming", Color: "#5686a5", Extension: ".glsl"},
+	{Name: "Git Config", Type: "data", Color: "#F44D27", Group: "INI", Extension: ".gitconfig", Aliases: []string{"gitconfig", "gitmodules"}},
+	{Name: "Gleam", Type: "programming", Color: "#ffaff3", Extension: ".gleam"},
+	{Name: "Go", Type: "programming", Color: "#00ADD8", Extension: ".go", Aliases: []string{"golang"}},
+	{Name: "Gradle", Type: "data", Color: "#02303a", Extension: ".gradle"},
+	{Name: "GraphQL", Type: "data", Color: "#e10098", Extension: ".graphql"},
+	{Name: "Groovy", Type: "programming", Color: "#4298b8", Extension: ".groovy"},
+	{Name: "HCL", Type: "programming", Color: "#844FBA", Extension: ".hcl", Aliases: []string{"HashiCorp Configuration Language", "terraform"}},
+	{Name: "HLSL", Type: "programming", Color: "#aace60", Extension: ".hlsl"},
+	{Name: "HTML", Type: "markup", Color: "#e34c26", Extension: ".html", Aliases: []string{"xhtml"}},
+	{Name: "Hack", Type: "programming", Color: "#878787", Extension: ".hack"},
+	{Name: "Haml", Type: "markup", Color: "#ece2a9", Extension: ".haml"},
+	{Name: "Handlebars", Type: "markup", Color: "#f7931e", Extension: ".handlebars", Aliases: []string{"hbs", "htmlbars"}},
+	{Name: "Haskell", Type: "programming", Color: "#5e5086", Extension: ".hs"},
+	{Name: "Haxe", Type: "programming", Color: "#df7900", Extension: ".hx"},
+	{Name: "INI", Type: "data", Color: "#d1dbe0", Extension: ".ini", Aliases: []string{"dosini"}},
+	{Name: "Idris", Type: "programming", Color: "#b30000", Extension: ".idr"},
+	{Name: "JSON", Type: "data", Color: "#292929", Extension: ".json", Aliases: []string{"geojson", "jsonl", "topojson"}},
+	{Name: "JSON with Comments", Type: "data", Color: "#292929", Group: "JSON", Extension: ".jsonc", Aliases: []string{"jsonc"}},
+	{Name: "JSON5", Type: "data", Color: "#267CB9", Extension: ".json5"},
+	{Name: "Java", Type: "programming", Color: "#b07219", Extension: ".java"},
+	{Name: "JavaScript", Type: "programming", Color: "#f1e05a", Extension: ".js", Aliases: []string{"js", "node"}},
+	{Name: "Jinja", Type: "markup", Color: "#a52a22", Extension: ".jinja", Aliases: []string{"django", "html+django", "html+jinja", "htmldjango"}},
+	{Name: "Jsonnet", Type: "programming", Color: "#0064bd", Extension: ".jsonnet"},
+	{Name: "Julia", Type: "programming", Color: "#a270ba", Extension: ".jl"},
+	{Name: "Jupyter Notebook", Type: "markup", Color: "#DA5B0B", Extension: ".ipynb", Aliases: []string{"IPython Notebook"}},
+	{Name: "Kotlin", Type: "programming", Color: "#A97BFF", Extension: ".kt"},
+	{Name: "LLVM", Type: "programming", Color: "#185619", Extension: ".ll"},
+	{Name: "Less", Type: "markup", Color: "#1d365d", Extension: ".less", Aliases: []string{"less-css"}},
+	{Name: "Liquid", Type: "markup", Color: "#67b8de", Extension: ".liquid"},
+	{Name: "Lua", Type: "programming", Color: "#000080", Extension: ".lua"},
+	{Name: "Luau", Type: "programming", Color: "#00A2FF", Extension: ".luau"},
+	{Name: "MATLAB", Type: "programming", Color: "#e16737", Extension: ".matlab", Aliases: []string{"octave"}},
+	{Name: "MDX", Type: "markup", Color: "#fcb32c", Extension: ".mdx"},
+	{Name: "Makefile", Type: "programming", Color: "#427819", Extension: ".mak", Aliases: []string{"bsdmake", "make", "mf"}},
+	{Name: "Markdown", Type: "prose", Color: "#083fa1", Extension: ".md", Aliases: []string{"md", "pandoc"}},
+	{Name: "Mermaid", Type: "markup", Color: "#ff3670", Extension: ".mmd", Aliases: []string{"mermaid example"}},
+	{Name: "Mojo", Type: "programming", Color: "#ff4c1f", Extension: ".mojo"},
+	{Name: "Move", Type: "programming", Color: "#4a137a", Extension: ".move"},
+	{Name: "Mustache", Type: "markup", Color: "#724b3b", Extension: ".mustache"},
+	{Name: "Nim", Type: "programming", Color: "#ffc200", Extension: ".nim"},
+	{Name: "Nix", Type: "programming", Color: "#7e7eff", Extension: ".nix", Aliases: []string{"nixos"}},
+	{Name: "Nunjucks", Type: "markup", Color: "#3d8137", Extension: ".njk", Aliases: []string{"njk"}},
+	{Name: "Nushell", Type: "programming", Color: "#4E9906", Extension: ".nu", Aliases: []string{"nu-script", "nush"}},
+	{Name: "OCaml", Type: "programming", Color: "#ef7a08", Extension: ".ml"},
+	{Name: "Objective-C", Type: "programming", Color: "#438eff", Extension: ".m", Aliases: []string{"obj-c", "objc", "objectivec"}},
+	{Name: "Objective-C++", Type: "programming", Color: "#6866fb", Extension: ".mm", Aliases: []string{"obj-c++", "objc++", "objectivec++"}},
+	{Name: "Odin", Type: "programming", Color: "#60AFFE", Extension: ".odin", Aliases: []string{"odinlang", "odin-lang"}},
+	{Name: "Org", Type: "prose", Color: "#77aa99", Extension: ".org"},
+	{Name: "PHP", Type: "programming", Color: "#4F5D95", Extension: ".php", Aliases: []string{"inc"}},
+	{Name: "PLSQL", Type: "programming", Color: "#dad8d8", Extension: ".pls"},
+	{Name: "PLpgSQL", Type: "programming", Color: "#336790", Extension: ".pgsql"},
+	{Name: "Pascal", Type: "programming", Color: "#E3F171", Extension: ".pas", Aliases: []string{"delphi", "objectpascal"}},
+	{Name: "Perl", Type: "programming", Color: "#0298c3", Extension: ".pl", Aliases: []string{"cperl"}},
+	{Name: "PowerShell", Type: "programming", Color: "#012456", Extension: ".ps1", Aliases: []string{"posh", "pwsh"}},
+	{Name: "Processing", Type: "programming", Color: "#0096D8", Extension: ".pde"},
+	{Name: "Prolog", Type: "programming", Color: "#74283c", Extension: ".pl"},
+	{Name: "Protocol Buffer", Type: "data", Color: "", Extension: ".proto", Aliases: []string{"proto", "protobuf", "Protocol Buffers"}},
+	{Name: "Pug", Type: "markup", Color: "#a86454", Extension: ".jade"},
+	{Name: "Puppet", Type: "programming", Color: "#302B6D", Extension: ".pp"},
+	{Name: "PureScript", Type: "programming", Color: "#1D222D", Extension: ".purs"},
+	{Name: "Python", Type: "programming", Color: "#3572A5", Extension: ".py", Aliases: []string{"python3", "rusthon"}},
+	{Name: "QML", Type: "programming", Color: "#44a51c", Extension: ".qml"},
+	{Name: "R", Type: "programming", Color: "#198CE7", Extension: ".r", Aliases: []string{"Rscript", "splus"}},
+	{Name: "Racket", Type: "programming", Color: "#3c5caa", Extension: ".rkt"},
+	{Name: "Raku", Type: "programming", Color: "#0000fb", Extension: ".6pl", Aliases: []string{"perl6", "perl-6"}},
+	{Name: "ReScript", Type: "programming", Color: "#ed5051", Extension: ".res"},
+	{Name: "Reason", Type: "programming", Color: "#ff5847", Extension: ".re"},
+	{Name: "Roff", Type: "markup", Color: "#ecdebe", Extension: ".roff", Aliases: []string{"groff", "man", "manpage", "man page", "man-page", "mdoc", "nroff", "troff"}},
+	{Name: "Ruby", Type: "programming", Color: "#701516", Extension: ".rb", Aliases: []string{"jruby", "macruby", "rake", "rb", "rbx"}},
+	{Name: "Rust", Type: "programming", Color: "#dea584", Extension: ".rs", Aliases: []string{"rs"}},
+	{Name: "SAS", Type: "programming", Color: "#B34936", Extension: ".sas"},
+	{Name: "SCSS", Type: "markup", Color: "#c6538c", Extension: ".scss"},
+	{Name: "SQL", Type: "data", Color: "#e38c00", Extension: ".sql"},
+	{Name: "Sass", Type: "markup", Color: "#a53b70", Extension: ".sass"},
+	{Name: "Scala", Type: "programming", Color: "#c22d40", Extension: ".scala"},
+	{Name: "Scheme", Type: "programming", Color: "#1e4aec", Extension: ".scm"},
+	{Name: "Shell", Type: "programming", Color: "#89e051", Extension: ".sh", Aliases: []string{"sh", "shell-script", "bash", "zsh"}},
+	{Name: "Slim", Type: "markup", Color: "#2b2b2b", Extension: ".slim"},
+	{Name: "Smalltalk", Type: "programming", Color: "#596706", Extension: ".st", Aliases: []string{"squeak"}},
+	{Name: "Smarty", Type: "programming", Color: "#f0c040", Extension: ".tpl"},
+	{Name: "Solidity", Type: "programming", Color: "#AA6746", Extension: ".sol"},
+	{Name: "Starlark", Type: "programming", Color: "#76d275", Extension: ".bzl", Aliases: []string{"bazel", "bzl"}},
+	{Name: "Stylus", Type: "markup", Color: "#ff6347", Extension: ".styl"},
+	{Name: "Svelte", Type: "markup", Color: "#ff3e00", Extension: ".svelte"},
+	{Name: "Swift", Type: "programming", Color: "#F05138", Extension: ".swift"},
+	{Name: "SystemVerilog", Type: "programming", Color: "#DAE1C2", Extension: ".sv"},
+	{Name: "TOML", Type: "data", Color: "#9c4221", Extension: ".toml"},
+	{Name: "TSQL", Type: "programming", Color: "#e38c00", Extension: ".sql"},
+	{Name: "TSX", Type: "programming", Color: "#3178c6", Group: "TypeScript", Extension: ".tsx"},
+	{Name: "Tcl", Type: "programming", Color: "#e4cc98", Extension: ".tcl"},
+	{Name: "TeX", Type: "markup", Color: "#3D6117", Extension: ".tex", Aliases: []string{"latex"}},
+	{Name: "Templ", Type: "markup", Color: "#66D0DD", Extension: ".templ"},
+	{Name: "Text", Type: "prose", Color: "", Extension: ".txt", Aliases: []string{"fundamental", "plain text"}},
+	{Name: "Twig", Type: "markup", Color: "#c1d026", Extension: ".twig"},
+	{Name: "TypeScript", Type: "programming", Color: "#3178c6", Extension: ".ts", Aliases: []string{"ts"}},
+	{Name: "Typst", Type: "markup", Color: "#239dad", Extension: ".typ", Aliases: []string{"typ"}},
+	{Name: "V", Type: "programming", Color: "#4f87c4", Extension: ".v", Aliases: []string{"vlang"}},
+	{Name: "VBA", Type: "programming", Color: "#867db1", Extension: ".bas", Aliases: []string{"visual basic for applications"}},
+	{Name: "VBScript", Type: "programming", Color: "#15dcdc", Extension: ".vbs"},
+	{Name: "VHDL", Type: "programming", Color: "#adb2cb", Extension: ".vhdl"},
+	{Name: "Vala", Type: "programming", Color: "#a56de2", Extension: ".vala"},
+	{Name: "Verilog", Type: "programming", Color: "#b2b7f8", Extension: ".v"},
+	{Name: "Vim Script", Type: "programming", Color: "#199f4b", Extension: ".vim", Aliases: []string{"vim", "viml", "nvim", "vimscript"}},
+	{Name: "Visual Basic .NET", Type: "programming", Color: "#945db7", Extension: ".vb", Aliases: []string{"vbnet", "vb .net", "vb.net"}},
+	{Name: "Vue", Type: "markup", Color: "#41b883", Extension: ".vue"},
+	{Name: "WebAssembly", Type: "programming", Color: "#04133b", Extension: ".wast", Aliases: []string{"wast", "wasm"}},
+	{Name: "XML", Type: "data", Color: "#0060ac", Extension: ".xml", Aliases: []string{"rss", "xsd", "wsdl"}},
+	{Name: "YAML", Type: "data", Color: "#cb171e", Extension: ".yml", Aliases: []string{"yml"}},
+	{Name: "Zig", Type: "programming", Color: "#ec915c", Extension: ".zig"},
+	{Name: "reStructuredText", Type: "prose", Color: "#141414", Extension: ".rst", Aliases: []string{"rst"}},
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLookupLanguage(t *testing.T) {
+	for _, name := range []string{"Go", "golang", " GO "} {
+		l, ok := LookupLanguage(name)
+		if !ok || l.Name != "Go" || l.Color != "#00ADD8" {
+			t.Errorf("LookupLanguage(%q) = %+v, %v", name, l, ok)
+		}
+	}
+	if _, ok := LookupLanguage("Klingon"); ok {
+		t.Error("unknown language found")
+	}
+	// Languages the demo profile uses, and common ones a trimmed table missed.
+	for _, name := range []string{"SQL", "INI", "Handlebars", "Sass", "Objective-C++", "Hack", "MDX", "TSX"} {
+		if l, ok := LookupLanguage(name); !ok || l.Color == "" {
+			t.Errorf("LookupLanguage(%q) = %+v, %v; want a coloured language", name, l, ok)
+		}
+	}
+	if set := iconSets[IconsNerd]; set.langIcon("golang") != set.langIcon("Go") {
+		t.Error("alias doesn't share its language's icon")
+	}
+}
+
+func TestGroupOtherLanguages(t *testing.T) {
+	ranked := []kv{{"Go", 5}, {"Rust", 3}, {"C", 2}, {"Lua", 1}}
+	got := groupOtherLanguages(ranked, 2)
+	want := []kv{{"Go", 5}, {"Rust", 3}, {"Other", 3}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+	if ranked[2].k != "C" {
+		t.Error("grouping changed its input")
+	}
+	if got := groupOtherLanguages(ranked, 0); len(got) != len(ranked) {
+		t.Errorf("n = 0 kept %d of %d", len(got), len(ranked))
+	}
+}
+
+func TestShareBar(t *testing.T) {
+	for _, tc := range []struct {
+		value, total int
+		want         string
+	}{
+		{1, 2, "#####     "},
+		{2, 2, "##########"},
+		{1, 1000, "#         "},
+		{0, 10, "          "},
+	} {
+		if got := shareBar(tc.value, tc.total, 10, 0, "Go", true); got != tc.want {
+			t.Errorf("shareBar(%d, %d) = %q, want %q", tc.value, tc.total, got, tc.want)
+		}
+	}
+}
+
+func TestBarCells(t *testing.T) {
+	for _, tc := range []struct {
+		values []int
+		width  int
+		want   []int
+	}{
+		// 10 cells of 60/25/15: 6, 2.5, 1.5 gives the odd cell to the larger share.
+		{[]int{60, 25, 15}, 10, []int{6, 3, 1}},
+		{[]int{1, 1, 1}, 10, []int{4, 3, 3}},
+		{[]int{97, 2, 1}, 10, []int{10, 0, 0}},
+	} {
+		kvs := make([]kv, len(tc.values))
+		total := 0
+		for i, v := range tc.values {
+			kvs[i] = kv{"x", v}
+			total += v
+		}
+		got := barCells(kvs, total, tc.width)
+		sum := 0
+		for i := range got {
+			sum += got[i]
+			if got[i] != tc.want[i] {
+				t.Errorf("barCells(%v, %d) = %v, want %v", tc.values, tc.width, got, tc.want)
+				break
+			}
+		}
+		if sum != tc.width {
+			t.Errorf("barCells(%v, %d) fills %d cells", tc.values, tc.width, sum)
+		}
+	}
+}
+
+func TestVendoredLanguageTable(t *testing.T) {
+	// languages_gen.go must be regenerated whenever the vendored file changes.
+	data, err := os.ReadFile("linguist/languages.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" && line != "---" && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-") {
+			names = append(names, strings.Trim(strings.TrimSuffix(line, ":"), `"`))
+		}
+	}
+	if len(names) != len(languages) {
+		t.Fatalf("languages.yml has %d languages, languages_gen.go %d; run go generate", len(names), len(languages))
+	}
+	types := []string{"programming", "markup", "data", "prose"}
+	seen := map[string]string{}
+	for i, l := range languages {
+		if l.Name != names[i] {
+			t.Errorf("language %d is %q in languages_gen.go, %q in languages.yml; run go generate", i, l.Name, names[i])
+		}
+		if !slices.Contains(types, l.Type) {
+			t.Errorf("%s has unknown type %q", l.Name, l.Type)
+		}
+		if l.Color != "" && (len(l.Color) != 7 || l.Color[0] != '#') {
+			t.Errorf("%s has malformed colour %q", l.Name, l.Color)
+		}
+		if l.Group != "" {
+			if _, ok := LookupLanguage(l.Group); !ok {
+				t.Errorf("%s is grouped under %q, which is not in the table", l.Name, l.Group)
+			}
+		}
+		for _, key := range append([]string{l.Name}, l.Aliases...) {
+			key = strings.ToLower(key)
+			if other, ok := seen[key]; ok {
+				t.Errorf("%q names both %s and %s", key, other, l.Name)
+			}
+			seen[key] = l.Name
+		}
+	}
+}
+
+func TestLanguageOutsideTable(t *testing.T) {
+	if _, ok := LookupLanguage("Brainfuck"); ok {
+		t.Skip("Brainfuck is in the table")
+	}
+	if got := langColor("Brainfuck", 2); got != langPalette[2] {
+		t.Errorf("langColor = %v, want the palette colour %v", got, langPalette[2])
+	}
+	if set := iconSets[IconsNerd]; set.langIcon("Brainfuck") != set.otherLang {
+		t.Error("expected a language outside the table to get the generic icon")
+	}
+}
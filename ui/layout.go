@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+)
+
+// cardLayout is how the card arranges its sections, picked from Options.Size.
+type cardLayout int
+
+const (
+	// layoutCompact fits a narrow card: one line per stat, language and repo,
+	// without URLs, badges or the contribution heatmap.
+	layoutCompact cardLayout = iota
+	// layoutSingle stacks every section in one column.
+	layoutSingle
+	// layoutColumns puts stats and languages side by side.
+	layoutColumns
+)
+
+const (
+	// panelChrome is the columns the border and margin add around the panel.
+	panelChrome = 2 + 4
+	// panelPadding is the columns the panel's padding takes from its width.
+	panelPadding = 4
+	// columnGap separates side-by-side sections.
+	columnGap = 4
+	// minColumnsWidth and maxCompactWidth are the content widths at which
+	// --size=full switches to columns and to the compact layout.
+	minColumnsWidth = 96
+	maxCompactWidth = 60
+)
+
+// terminalWidth returns stdout's width, or 0 when it isn't a terminal.
+func terminalWidth() int {
+	w, _, err := term.GetSize(os.Stdout.Fd())
+	if err != nil {
+		return 0
+	}
+	return w
+}
+
+// panelWidth returns the panel width for size, 0 meaning unconstrained. Full
+// fills the terminal.
+func panelWidth(size string) int {
+	switch strings.ToLower(size) {
+	case "small":
+		return 48
+	case "large":
+		return 120
+	case "full":
+		if w := terminalWidth(); w > panelChrome {
+			return w - panelChrome
+		}
+		return 0
+	default:
+		return 88
+	}
+}
+
+// contentWidth returns how many columns a line of the card's content may take,
+// 0 meaning unlimited. Without a border the content is as wide as the panel
+// would have been, or the terminal for full.
+func contentWidth(size string, bordered bool) int {
+	if !bordered {
+		if strings.ToLower(size) == "full" {
+			return terminalWidth()
+		}
+		return panelWidth(size)
+	}
+	w := panelWidth(size)
+	if w == 0 {
+		return 0
+	}
+	return w - panelPadding
+}
+
+// layoutFor picks the layout for size. Full follows the content width.
+func layoutFor(size string, width int) cardLayout {
+	switch strings.ToLower(size) {
+	case "small":
+		return layoutCompact
+	case "large":
+		return layoutColumns
+	case "full":
+		switch {
+		case width == 0 || width >= minColumnsWidth:
+			return layoutColumns
+		case width < maxCompactWidth:
+			return layoutCompact
+		}
+	}
+	return layoutSingle
+}
+
+// sideBySide joins left and right as two top-aligned columns when they fit in
+// width, and stacks them with a blank line between otherwise.
+func sideBySide(left, right string, width int) string {
+	left, right = strings.TrimSuffix(left, "\n"), strings.TrimSuffix(right, "\n")
+	switch {
+	case left == "":
+		return right + "\n"
+	case right == "":
+		return left + "\n"
+	case width > 0 && lipgloss.Width(left)+columnGap+lipgloss.Width(right) > width:
+		return left + "\n\n" + right + "\n"
+	}
+	gap := strings.Repeat(" ", columnGap)
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, gap, right) + "\n"
+}
+
+// truncateLines cuts every line of s to width columns, ending cut lines with
+// an ellipsis, so long bios, descriptions and URLs don't wrap. Lines that only
+// overflow with padding are cut without one.
+func truncateLines(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if ansi.StringWidth(line) <= width {
+			continue
+		}
+		if strings.TrimSpace(ansi.Strip(ansi.TruncateLeft(line, width, ""))) == "" {
+			lines[i] = ansi.Truncate(line, width, "")
+		} else {
+			lines[i] = ansi.Truncate(line, width, "…")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestLayoutForSize(t *testing.T) {
+	for _, tc := range []struct {
+		size     string
+		bordered bool
+		width    int
+		layout   cardLayout
+	}{
+		{"small", true, 44, layoutCompact},
+		{"small", false, 48, layoutCompact},
+		{"medium", true, 84, layoutSingle},
+		{"", false, 88, layoutSingle},
+		{"LARGE", true, 116, layoutColumns},
+		{"large", false, 120, layoutColumns},
+	} {
+		width := contentWidth(tc.size, tc.bordered)
+		if width != tc.width {
+			t.Errorf("contentWidth(%q, %v) = %d, want %d", tc.size, tc.bordered, width, tc.width)
+		}
+		if got := layoutFor(tc.size, width); got != tc.layout {
+			t.Errorf("layoutFor(%q, %d) = %d, want %d", tc.size, width, got, tc.layout)
+		}
+	}
+}
+
+func TestLayoutForFullFollowsWidth(t *testing.T) {
+	for _, tc := range []struct {
+		width  int
+		layout cardLayout
+	}{
+		{0, layoutColumns},
+		{maxCompactWidth - 1, layoutCompact},
+		{maxCompactWidth, layoutSingle},
+		{minColumnsWidth - 1, layoutSingle},
+		{minColumnsWidth, layoutColumns},
+		{200, layoutColumns},
+	} {
+		if got := layoutFor("full", tc.width); got != tc.layout {
+			t.Errorf("layoutFor(full, %d) = %d, want %d", tc.width, got, tc.layout)
+		}
+	}
+}
+
+func TestTruncateLines(t *testing.T) {
+	const bold, reset = "\x1b[1m", "\x1b[0m"
+	for _, tc := range []struct {
+		name, in string
+		width    int
+		want     string
+	}{
+		{"fits", "short\nlines", 10, "short\nlines"},
+		{"unlimited", strings.Repeat("x", 100), 0, strings.Repeat("x", 100)},
+		{"cut with ellipsis", "a long description", 10, "a long de…"},
+		{"only padding overflows", "padded" + strings.Repeat(" ", 10), 8, "padded  "},
+		{"styled", bold + "a long description" + reset, 10, bold + "a long de…" + reset},
+		{"styled padding", bold + "padded" + reset + strings.Repeat(" ", 10), 8, bold + "padded" + reset + "  "},
+		{"per line", "fits\n" + "this one does not", 8, "fits\nthis on…"},
+	} {
+		got := truncateLines(tc.in, tc.width)
+		if got != tc.want {
+			t.Errorf("%s: truncateLines(%q, %d) = %q, want %q", tc.name, tc.in, tc.width, got, tc.want)
+		}
+		if tc.width > 0 {
+			for _, line := range strings.Split(got, "\n") {
+				if ansi.StringWidth(line) > tc.width {
+					t.Errorf("%s: line %q is wider than %d", tc.name, line, tc.width)
+				}
+			}
+		}
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	left, right := "Stats:\nStars: 5\n", "Languages:\nGo\n"
+	for _, tc := range []struct {
+		name        string
+		left, right string
+		width       int
+		want        string
+	}{
+		{"columns", left, right, 40, "Stats:      Languages:\nStars: 5    Go        \n"},
+		{"unlimited", left, right, 0, "Stats:      Languages:\nStars: 5    Go        \n"},
+		{"too narrow stacks", left, right, 20, "Stats:\nStars: 5\n\nLanguages:\nGo\n"},
+		{"exact fit", left, right, 8 + columnGap + 10, "Stats:      Languages:\nStars: 5    Go        \n"},
+		{"no left", "", right, 40, right},
+		{"no right", left, "", 40, left},
+	} {
+		if got := sideBySide(tc.left, tc.right, tc.width); got != tc.want {
+			t.Errorf("%s: sideBySide(width %d) = %q, want %q", tc.name, tc.width, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"ghprofile/github"
+)
+
+// RenderMarkdown renders the profile card as GitHub-flavoured Markdown, with the
+// same sections, in the same order, and repo list as PrintProfile. There is no
+// info section; the stats table ends with the join date instead.
+func RenderMarkdown(p *github.Profile, repos []github.Repo, opts Options) string {
+	if p == nil {
+		return "No profile\n"
+	}
+	c := &card{p: p, list: repos, opts: opts}
+	names := opts.Sections
+	if len(names) == 0 {
+		names = Sections
+	}
+	var b strings.Builder
+	done := map[string]bool{}
+	for _, name := range names {
+		if write := markdownSections[name]; write != nil && !done[name] {
+			done[name] = true
+			write(&b, c)
+		}
+	}
+	return strings.TrimPrefix(b.String(), "\n")
+}
+
+// markdownSections write each of Sections that the Markdown card has. Every
+// section but the header starts with a blank line.
+var markdownSections = map[string]func(b *strings.Builder, c *card){
+	"header": func(b *strings.Builder, c *card) {
+		name := c.p.FullName
+		if name == "" {
+			name = c.p.Name
+		}
+		fmt.Fprintf(b, "\n# [%s](%s)\n", mdEscape(name), c.p.URL)
+		if c.p.Bio != "" {
+			fmt.Fprintf(b, "\n> %s\n", mdEscape(c.p.Bio))
+		}
+	},
+	"stats": func(b *strings.Builder, c *card) {
+		b.WriteString("\n| Stat | Value |\n|---|---:|\n")
+		for _, row := range statRows(c.p, c.opts.Stats) {
+			fmt.Fprintf(b, "| %s | %s |\n", row[0], row[1])
+		}
+	},
+	"contributions": func(b *strings.Builder, c *card) {
+		if cal := c.p.Contributions; cal != nil && len(cal.Weeks) > 0 {
+			current, longest := cal.Streaks()
+			fmt.Fprintf(b, "\n**%d contributions %s** · current streak %s · longest streak %s\n",
+				cal.TotalContributions, calendarPeriod(cal), plural(current, "day"), plural(longest, "day"))
+		}
+	},
+	"languages": writeMarkdownLanguages,
+	"pinned": func(b *strings.Builder, c *card) {
+		if len(c.p.Pinned) > 0 {
+			writeMarkdownRepos(b, "Pinned", c.p.Pinned, len(c.p.Pinned))
+		}
+	},
+	"repos": func(b *strings.Builder, c *card) {
+		if title, top, ok := repoListing(c.p, c.list, c.opts); ok {
+			writeMarkdownRepos(b, strings.TrimSuffix(title, ":"), top, c.opts.TopN)
+		}
+	},
+	"activity": func(b *strings.Builder, c *card) {
+		if len(c.p.Events) > 0 || c.opts.Activity {
+			act := github.SummarizeActivity(c.p.Events, c.opts.now().Add(-github.ActivityWindow))
+			fmt.Fprintf(b, "\n## Activity (last %d days)\n\n", int(github.ActivityWindow.Hours()/24))
+			fmt.Fprintf(b, "- Commits pushed: %d\n- PRs opened: %d\n- PRs merged: %d\n- Issues opened: %d\n- Repos created: %d\n- Releases: %d\n- Repos starred: %d\n",
+				act.Commits, act.PRsOpened, act.PRsMerged, act.IssuesOpened, act.ReposCreated, act.Releases, act.StarsGiven)
+		}
+	},
+	"gists": func(b *strings.Builder, c *card) {
+		if len(c.p.Gists) == 0 {
+			return
+		}
+		b.WriteString("\n## Gists\n\n")
+		for i, g := range c.p.Gists {
+			if i == maxGists {
+				break
+			}
+			fmt.Fprintf(b, "- [%s](%s) · %s · %s\n", mdEscape(gistTitle(g)), g.HTMLURL, plural(g.FileCount(), "file"), plural(g.Comments, "comment"))
+		}
+	},
+	"starred": func(b *strings.Builder, c *card) {
+		if len(c.p.Starred) == 0 {
+			return
+		}
+		b.WriteString("\n## Recently starred\n\n")
+		for i, s := range c.p.Starred {
+			if i == maxStarred {
+				break
+			}
+			fmt.Fprintf(b, "- [%s](%s) ★ %d (%s)\n", s.Repo.FullName, s.Repo.HTMLURL, s.Repo.StargazersCount, shortDate(s.StarredAt))
+		}
+	},
+}
+
+// PrintMarkdown writes RenderMarkdown's output to stdout.
+func PrintMarkdown(p *github.Profile, repos []github.Repo, opts Options) {
+	fmt.Print(RenderMarkdown(p, repos, opts))
+}
+
+// writeMarkdownLanguages lists the card's languages weighed and grouped as in
+// the text card, with their share and, unless weighed by bytes, their count.
+func writeMarkdownLanguages(b *strings.Builder, c *card) {
+	weights, heading, counted := c.languageWeights()
+	total := 0
+	for _, n := range weights {
+		total += n
+	}
+	if total == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n", strings.TrimSuffix(heading, ":"))
+	for _, x := range groupOtherLanguages(rankCounts(weights, 0), c.opts.TopLangs) {
+		fmt.Fprintf(b, "- %s: %.1f%%", x.k, float64(x.v)*100/float64(total))
+		if counted {
+			fmt.Fprintf(b, " (%d)", x.v)
+		}
+		b.WriteString("\n")
+	}
+}
+
+func writeMarkdownRepos(b *strings.Builder, title string, repos []github.Repo, n int) {
+	if n > len(repos) {
+		n = len(repos)
+	}
+	if n <= 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n| Repo | Language | Stars | Forks |\n|---|---|---:|---:|\n", title)
+	for _, r := range repos[:n] {
+		name := fmt.Sprintf("[%s](%s)", r.FullName, r.HTMLURL)
+		if badges := repoBadges(r); len(badges) > 0 {
+			name += " `" + strings.Join(badges, "` `") + "`"
+		}
+		if r.Private {
+			name += " 🔒"
+		}
+		fmt.Fprintf(b, "| %s | %s | %d | %d |\n", name, r.Language, r.StargazersCount, r.ForksCount)
+	}
+}
+
+// statRows returns the stats named by names, from StatNames, as label/value
+// pairs for the Markdown card, followed by the join date. Empty names means
+// all; "private" is only shown for the token owner, as on the text card.
+func statRows(p *github.Profile, names []string) [][2]string {
+	if len(names) == 0 {
+		names = StatNames
+	}
+	totalStars, totalForks, avg := 0, 0, float32(0)
+	if p.TotalStars != nil {
+		totalStars = *p.TotalStars
+	}
+	if p.TotalForks != nil {
+		totalForks = *p.TotalForks
+	}
+	if p.AvgStarsPerRepo != nil {
+		avg = *p.AvgStarsPerRepo
+	}
+	all := map[string][2]string{
+		"followers": {"Followers", fmt.Sprintf("%d", p.FollowersAmount)},
+		"following": {"Following", fmt.Sprintf("%d", p.FollowingAmount)},
+		"repos":     {"Public repos", fmt.Sprintf("%d", p.PublicReposAmount)},
+		"private":   {"Private repos", privateRepos(p)},
+		"gists":     {"Public gists", fmt.Sprintf("%d", p.PublicGistsAmount)},
+		"stars":     {"Total stars", fmt.Sprintf("%d", totalStars)},
+		"forks":     {"Total forks", fmt.Sprintf("%d", totalForks)},
+		"avg-stars": {"Avg stars/repo", fmt.Sprintf("%.2f", avg)},
+	}
+	var rows [][2]string
+	for _, name := range names {
+		if name == "private" && !p.IsAuthenticatedUser {
+			continue
+		}
+		if row, ok := all[name]; ok {
+			rows = append(rows, row)
+		}
+	}
+	if joined, ok := p.JoinedAt(); ok {
+		rows = append(rows, [2]string{"Member since", joined.Format("2006-01-02")})
+	}
+	return rows
+}
+
+// mdEscape keeps user text from breaking Markdown tables and links.
+func mdEscape(s string) string {
+	r := strings.NewReplacer("|", "\\|", "[", "\\[", "]", "\\]", "\r\n", " ", "\n", " ")
+	return r.Replace(s)
+}
+
+// RenderRepoMarkdown renders the repo card as GitHub-flavoured Markdown.
+func RenderRepoMarkdown(d *github.RepoDetail, opts Options) string {
+	if d == nil {
+		return "No repo\n"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# [%s](%s)\n\n", d.FullName, d.HTMLURL)
+	if d.Description != "" {
+		fmt.Fprintf(&b, "> %s\n\n", mdEscape(d.Description))
+	}
+	if badges := repoBadges(d.Repo); len(badges) > 0 {
+		b.WriteString("`" + strings.Join(badges, "` `") + "`\n\n")
+	}
+
+	b.WriteString("| Stat | Value |\n|---|---:|\n")
+	fmt.Fprintf(&b, "| Stars | %d |\n| Forks | %d |\n| Watchers | %d |\n", d.StargazersCount, d.ForksCount, d.SubscribersCount)
+	if d.OpenIssues != nil && d.OpenPulls != nil {
+		fmt.Fprintf(&b, "| Open issues | %d |\n| Open PRs | %d |\n", *d.OpenIssues, *d.OpenPulls)
+	} else {
+		fmt.Fprintf(&b, "| Open issues+PRs | %d |\n", d.OpenIssuesCount)
+	}
+	if d.PushedAt != "" {
+		fmt.Fprintf(&b, "| Last push | %s |\n", shortDate(d.PushedAt))
+	}
+
+	opts.LangBytes = true
+	writeMarkdownLanguages(&b, &card{list: []github.Repo{d.Repo}, opts: opts})
+
+	if rel := d.LatestRelease; rel != nil {
+		fmt.Fprintf(&b, "\n## Latest release\n\n[%s](%s) · %s\n", mdEscape(rel.TagName), rel.HTMLURL, shortDate(rel.PublishedAt))
+	}
+
+	if len(d.Contributors) > 0 {
+		b.WriteString("\n## Top contributors\n\n| Contributor | Commits |\n|---|---:|\n")
+		for _, p := range d.Contributors {
+			fmt.Fprintf(&b, "| [%s](%s) | %d |\n", p.Login, p.HTMLURL, p.Contributions)
+		}
+	}
+
+	if len(d.Commits) > 0 {
+		b.WriteString("\n## Recent commits\n\n")
+		for i, c := range d.Commits {
+			if i == maxRepoCommits {
+				break
+			}
+			sha := shortSHA(c.SHA)
+			fmt.Fprintf(&b, "- [`%s`](%s) %s (%s, %s)\n", sha, c.HTMLURL, mdEscape(c.Message), mdEscape(c.Author), shortDate(c.Date))
+		}
+	}
+	return b.String()
+}
@@ -2,61 +2,30 @@ package ui
 
 import (
 	"fmt"
+	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"ghprofile/github"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
 )
 
-func PrintProfile(p *github.Profile, repos []github.Repo, topN int, showIcons bool, noBorder bool, noStyle bool, size string) {
-	if p == nil {
-		fmt.Println("No profile")
-		return
-	}
+const (
+	// maxGists caps how many gists are listed in the Gists section.
+	maxGists = github.MaxGists
+	// maxStarred caps the "Recently starred" list; maxStarredTags caps the
+	// language and topic summaries below it.
+	maxStarred     = 5
+	maxStarredTags = 5
+)
 
-	var b strings.Builder
-	title := TitleStyle.Render(fmt.Sprintf("%s", p.FullName))
-	url := URLStyle.Render(p.URL)
-	iconRender := func(s string) string {
-		if !showIcons || s == "" {
-			return ""
-		}
-		return IconStyle.Render(s)
-	}
-	if iconRender(IconUser) != "" {
-		b.WriteString(iconRender(IconUser) + "  " + title + "  " + url + "\n")
-	} else {
-		b.WriteString(title + "  " + url + "\n")
-	}
-	if p.Bio != "" {
-		b.WriteString(Subtle.Render(p.Bio) + "\n")
-	}
-	b.WriteString("\n")
-	type statEntry struct{ icon, label, value string }
-	totalStars := 0
-	totalForks := 0
-	if p.TotalStars != nil {
-		totalStars = *p.TotalStars
-	}
-	if p.TotalForks != nil {
-		totalForks = *p.TotalForks
-	}
-	avg := float32(0)
-	if p.AvgStarsPerRepo != nil {
-		avg = *p.AvgStarsPerRepo
-	}
+type statEntry struct{ icon, label, value string }
 
-	stats := []statEntry{
-		{iconRender(IconFollowers), "Followers:", fmt.Sprintf("%d", p.FollowersAmount)},
-		{iconRender(IconFollowing), "Following:", fmt.Sprintf("%d", p.FollowingAmount)},
-		{iconRender(IconRepo), "Public repos:", fmt.Sprintf("%d", p.PublicReposAmount)},
-		{iconRender(IconGist), "Public gists:", fmt.Sprintf("%d", p.PublicGistsAmount)},
-		{iconRender(IconStar), "Total stars:", fmt.Sprintf("%d", totalStars)},
-		{iconRender(IconFork), "Total forks:", fmt.Sprintf("%d", totalForks)},
-		{iconRender(IconStar), "Avg stars/repo:", fmt.Sprintf("%.2f", avg)},
-	}
+// writeStats writes one aligned "icon label value" line per entry.
+func writeStats(b *strings.Builder, stats []statEntry) {
 	maxLabel := 0
 	for _, s := range stats {
 		if len(s.label) > maxLabel {
@@ -72,82 +41,509 @@ func PrintProfile(p *github.Profile, repos []github.Repo, topN int, showIcons bo
 		padded := fmt.Sprintf("%-*s", maxLabel, s.label)
 		b.WriteString(Accent.Render(padded) + " " + ValueStyle.Render(s.value) + "\n")
 	}
+}
+
+// repoListing returns the repo list shown after the Pinned section, filtered
+// by opts.Filter and sorted per opts.TopBy, with its title. ok is false when the
+// pinned repos replace the list.
+func repoListing(p *github.Profile, repos []github.Repo, opts Options) (title string, top []github.Repo, ok bool) {
+	by := opts.TopBy
+	if by == "pinned" {
+		if len(p.Pinned) > 0 {
+			return "", nil, false
+		}
+		by = "stars"
+	}
+	top = github.FilterRepos(repos, opts.Filter)
+	github.SortRepos(top, by, opts.Ascending)
+	return repoListTitle(by, opts.Ascending), top, true
+}
+
+// repoListTitle names the repo list for its sort order.
+func repoListTitle(by string, ascending bool) string {
+	switch {
+	case (by == "stars" || by == "") && !ascending:
+		return "Top repos:"
+	case by == "updated" && !ascending:
+		return "Recently updated repos:"
+	case ascending:
+		return fmt.Sprintf("Repos by %s (ascending):", by)
+	default:
+		return fmt.Sprintf("Repos by %s:", by)
+	}
+}
 
+// writeRepoList writes a titled, numbered list of at most n repos.
+// The compact layout gives each repo a single line.
+func writeRepoList(b *strings.Builder, title string, repos []github.Repo, n int, opts Options, layout cardLayout, iconRender func(string) string) {
+	if n > len(repos) {
+		n = len(repos)
+	}
+	if n <= 0 {
+		return
+	}
+	set := opts.icons()
+	b.WriteString("\n")
+	if opts.NoStyle {
+		b.WriteString(title + "\n")
+	} else {
+		b.WriteString(Subtle.Render(title) + "\n")
+	}
+	for i := 0; i < n; i++ {
+		r := repos[i]
+		badges := repoBadges(r)
+		starsDelta, forksDelta := repoChanges(opts, r.FullName)
+		if opts.NoStyle {
+			name := r.FullName
+			if r.Private {
+				name += " (private)"
+			}
+			b.WriteString(fmt.Sprintf("%d. %s %s %s %d%s  %s %d%s\n", i+1, name, r.Language, set.stargazers, r.StargazersCount, changeMark(starsDelta, opts), set.fork, r.ForksCount, changeMark(forksDelta, opts)))
+			if layout == layoutCompact {
+				continue
+			}
+			if len(badges) > 0 {
+				b.WriteString("  [" + strings.Join(badges, "] [") + "]\n")
+			}
+			b.WriteString("  " + r.HTMLURL + "\n")
+			if r.Homepage != "" {
+				b.WriteString("  " + r.Homepage + "\n")
+			}
+		} else {
+			name := RepoTitle.Render(r.FullName)
+			if r.Private {
+				if lock := iconRender(set.lock); lock != "" {
+					name = lock + " " + name
+				} else {
+					name += " (private)"
+				}
+			}
+			b.WriteString(fmt.Sprintf("%d. %s %s %s %d%s  %s %d%s\n", i+1, name, repoLanguage(r.Language, set, opts.ShowIcons), iconRender(set.stargazers), r.StargazersCount, changeMark(starsDelta, opts), iconRender(set.fork), r.ForksCount, changeMark(forksDelta, opts)))
+			if layout == layoutCompact {
+				continue
+			}
+			if len(badges) > 0 {
+				rendered := make([]string, len(badges))
+				for j, badge := range badges {
+					rendered[j] = Badge.Render(badge)
+				}
+				b.WriteString("  " + strings.Join(rendered, "") + "\n")
+			}
+			b.WriteString("  " + URLStyle.Render(r.HTMLURL) + "\n")
+			if r.Homepage != "" {
+				b.WriteString("  " + iconRender(set.link) + " " + URLStyle.Render(r.Homepage) + "\n")
+			}
+		}
+	}
+}
+
+// repoLanguage shows a repo's language in its colour: as its icon from set when
+// icons are on, else by name.
+func repoLanguage(lang string, set iconSet, icons bool) string {
+	if lang == "" {
+		return ""
+	}
+	style := IconStyle
+	if l, ok := LookupLanguage(lang); ok && l.Color != "" {
+		style = style.Foreground(lipgloss.Color(l.Color))
+	}
+	if icons {
+		return style.Render(set.langIcon(lang))
+	}
+	return style.Render(lang)
+}
+
+// maxTopicBadges caps how many topics are shown as badges per repo.
+const maxTopicBadges = 4
+
+// repoBadges lists the short labels shown under a repo: its state, license,
+// internal visibility, pages and topics. Private repos are marked on the name line.
+func repoBadges(r github.Repo) []string {
+	var badges []string
+	if r.Archived {
+		badges = append(badges, "archived")
+	}
+	if r.IsTemplate {
+		badges = append(badges, "template")
+	}
+	if r.MirrorURL != "" {
+		badges = append(badges, "mirror")
+	}
+	if r.Fork {
+		if r.Parent != nil && r.Parent.FullName != "" {
+			badges = append(badges, "fork of "+r.Parent.FullName)
+		} else {
+			badges = append(badges, "fork")
+		}
+	}
+	if r.Visibility == "internal" {
+		badges = append(badges, r.Visibility)
+	}
+	// GitHub reports unrecognised licenses as NOASSERTION.
+	if r.License != nil && r.License.SPDXID != "" && r.License.SPDXID != "NOASSERTION" {
+		badges = append(badges, r.License.SPDXID)
+	}
+	if r.HasPages {
+		badges = append(badges, "pages")
+	}
+	for i, t := range r.Topics {
+		if i == maxTopicBadges {
+			badges = append(badges, "+"+plural(len(r.Topics)-i, "topic"))
+			break
+		}
+		badges = append(badges, "#"+t)
+	}
+	return badges
+}
+
+// langBarWidth is the width in cells of the stacked language bar, and
+// langRowBarWidth that of the bar on each language's row.
+const (
+	langBarWidth    = 50
+	langRowBarWidth = 20
+)
+
+// otherLanguages names the entry groupOtherLanguages folds the rest into.
+const otherLanguages = "Other"
+
+// Language weights for Options.LangWeight.
+const (
+	LangWeightRepos = "repos"
+	LangWeightStars = "stars"
+)
+
+// plainBarGlyphs tell the bar's segments apart when styles are disabled.
+var plainBarGlyphs = []string{"#", "=", "*", "+", "-", "~", "%", "@"}
+
+// repoLanguageCounts counts repos per primary language.
+func repoLanguageCounts(repos []github.Repo) map[string]int {
 	langCount := map[string]int{}
 	for _, r := range repos {
-		lang := r.Language
-		if lang == "" {
+		if r.Language == "" {
 			continue // ignore repos with no language
 		}
-		langCount[lang]++
-	}
-	if len(langCount) > 0 {
-		b.WriteString("\n")
-		b.WriteString(Subtle.Render("Languages:") + "\n")
-		type kv struct {
-			k string
-			v int
+		langCount[r.Language]++
+	}
+	return langCount
+}
+
+// languageBar renders kvs as one bar of width cells, each entry taking a share
+// proportional to its value. Cells are apportioned by largest remainder, so
+// rounding never favours the smallest entry. Entries too small for a cell are
+// dropped.
+func languageBar(kvs []kv, total, width int, noStyle bool) string {
+	if total <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	for i, cells := range barCells(kvs, total, width) {
+		if cells <= 0 {
+			continue
 		}
-		var kvs []kv
-		for k, v := range langCount {
-			kvs = append(kvs, kv{k, v})
+		x := kvs[i]
+		if noStyle {
+			b.WriteString(strings.Repeat(plainBarGlyphs[i%len(plainBarGlyphs)], cells))
+		} else {
+			b.WriteString(lipgloss.NewStyle().Foreground(barColor(x.k, i)).Render(strings.Repeat("█", cells)))
 		}
-		sort.Slice(kvs, func(i, j int) bool { return kvs[i].v > kvs[j].v })
-		for _, x := range kvs {
-			icon := GetLangIcon(x.k)
-			out := fmt.Sprintf("%s  %s: %d\n", iconRender(icon), Accent.Render(x.k), x.v)
-			if icon == "" {
-				out = fmt.Sprintf("   %s: %d\n", Accent.Render(x.k), x.v)
+	}
+	return b.String()
+}
+
+// barCells splits width cells between kvs in proportion to their values. Each
+// entry gets the whole cells of its share, and the cells left over go to the
+// entries with the largest fractions, earlier entries first on ties.
+func barCells(kvs []kv, total, width int) []int {
+	cells := make([]int, len(kvs))
+	fracs := make([]float64, len(kvs))
+	used := 0
+	for i, x := range kvs {
+		exact := float64(x.v) * float64(width) / float64(total)
+		cells[i] = int(exact)
+		fracs[i] = exact - float64(cells[i])
+		used += cells[i]
+	}
+	order := make([]int, len(kvs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return fracs[order[a]] > fracs[order[b]] })
+	for _, i := range order {
+		if used >= width {
+			break
+		}
+		cells[i]++
+		used++
+	}
+	return cells
+}
+
+// barEighths are the partial blocks that end a shareBar, by eighths of a cell.
+var barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// shareBar renders value's share of total as a bar of width cells, in eighths
+// of a cell, on a dim track. rank and lang pick its colour or, without styles,
+// the same glyph as the language's segment of languageBar.
+func shareBar(value, total, width, rank int, lang string, noStyle bool) string {
+	if total <= 0 || width <= 0 {
+		return ""
+	}
+	eighths := int(float64(value)*float64(width*8)/float64(total) + 0.5)
+	if value > 0 && eighths == 0 {
+		eighths = 1 // keep every listed language visible
+	}
+	if noStyle {
+		cells := (eighths + 4) / 8
+		if value > 0 && cells == 0 {
+			cells = 1
+		}
+		return strings.Repeat(plainBarGlyphs[rank%len(plainBarGlyphs)], cells) + strings.Repeat(" ", width-cells)
+	}
+	bar := strings.Repeat("█", eighths/8) + barEighths[eighths%8]
+	track := strings.Repeat(" ", width-lipgloss.Width(bar))
+	return lipgloss.NewStyle().Foreground(barColor(lang, rank)).Background(headerBg).Render(bar) +
+		lipgloss.NewStyle().Background(headerBg).Render(track)
+}
+
+// barColor is langColor, with the "Other" entry greyed out.
+func barColor(lang string, rank int) lipgloss.Color {
+	if lang == otherLanguages {
+		return subtleFg
+	}
+	return langColor(lang, rank)
+}
+
+// groupOtherLanguages keeps the first n of ranked and folds the rest into one
+// "Other" entry. n <= 0 keeps them all.
+func groupOtherLanguages(ranked []kv, n int) []kv {
+	if n <= 0 || len(ranked) <= n {
+		return ranked
+	}
+	rest := 0
+	for _, x := range ranked[n:] {
+		rest += x.v
+	}
+	return append(ranked[:n:n], kv{otherLanguages, rest})
+}
+
+type kv struct {
+	k string
+	v int
+}
+
+// rankCounts sorts a count map by count, descending, breaking ties by name.
+// n limits the result; 0 means no limit.
+func rankCounts(m map[string]int, n int) []kv {
+	kvs := make([]kv, 0, len(m))
+	for k, v := range m {
+		kvs = append(kvs, kv{k, v})
+	}
+	sort.Slice(kvs, func(i, j int) bool {
+		if kvs[i].v != kvs[j].v {
+			return kvs[i].v > kvs[j].v
+		}
+		return kvs[i].k < kvs[j].k
+	})
+	if n > 0 && len(kvs) > n {
+		kvs = kvs[:n]
+	}
+	return kvs
+}
+
+// Options controls how PrintProfile renders the card.
+type Options struct {
+	TopN      int
+	ShowIcons bool
+	NoBorder  bool
+	NoStyle   bool
+	// Icons is the icon set, from IconSets, whose icons ShowIcons shows.
+	// Empty means nerd.
+	Icons string
+	// Size is small, medium, large or full.
+	Size string
+	// LangBytes shows the Languages section as shares of bytes of code, using
+	// Repo.Languages, instead of counting repos by primary language.
+	LangBytes bool
+	// LangWeight weighs languages by LangWeightRepos (the default) or by
+	// LangWeightStars, the stars of their repos. TopLangs caps the languages
+	// shown, folding the rest into "Other"; 0 shows them all.
+	LangWeight string
+	TopLangs   int
+	// TopBy picks the repo list: "pinned" or one of github.SortKeys. Pinned
+	// falls back to stars when the profile has no pinned repos.
+	TopBy string
+	// Ascending reverses the TopBy order, e.g. least starred first.
+	Ascending bool
+	// Filter picks the repos of that list. Languages and totals still count
+	// every repo.
+	Filter github.RepoFilter
+	// Changes marks followers, stars and forks that changed since an earlier
+	// fetch, as in watch mode. Flash renders those marks in reverse video.
+	Changes *github.SnapshotDiff
+	Flash   bool
+	// Avatar is drawn left of the name unless NoStyle is set. ImageProtocol
+	// picks how PrintProfile draws it; see DetectImageProtocol.
+	Avatar        *Avatar
+	ImageProtocol string
+	// Sections picks the card's sections and their order, from Sections.
+	// Stats picks the rows of the stats section, from StatNames. Empty means all.
+	Sections []string
+	Stats    []string
+	// Activity says recent activity was fetched, so the activity section
+	// shows zero counts rather than disappearing when there are no events.
+	Activity bool
+	// Now is the time account age and the activity window are measured up to,
+	// such as a demo profile's reference time. Zero means the current time.
+	Now time.Time
+}
+
+// now returns Options.Now, or the current time when it is unset.
+func (o Options) now() time.Time {
+	if o.Now.IsZero() {
+		return time.Now()
+	}
+	return o.Now
+}
+
+// changeMark renders a non-zero delta as " (+3)" for appending to a value.
+func changeMark(delta int, opts Options) string {
+	if delta == 0 {
+		return ""
+	}
+	mark := "(" + signed(delta) + ")"
+	if opts.NoStyle {
+		return " " + mark
+	}
+	style := deltaStyle(delta)
+	if opts.Flash {
+		style = style.Reverse(true)
+	}
+	return " " + style.Render(mark)
+}
+
+// repoChanges returns the star and fork deltas of a repo from opts.Changes.
+func repoChanges(opts Options, fullName string) (stars, forks int) {
+	if opts.Changes == nil {
+		return 0, 0
+	}
+	for _, d := range opts.Changes.RepoDeltas {
+		if d.FullName == fullName {
+			return d.StarsDelta, d.ForksDelta
+		}
+	}
+	return 0, 0
+}
+
+func PrintProfile(p *github.Profile, repos []github.Repo, opts Options) {
+	if opts.Avatar != nil && !opts.NoStyle && opts.ImageProtocol != "" && opts.ImageProtocol != ImageBlocks {
+		// Graphics can't be embedded in the card's text, so leave room for the
+		// avatar and draw it there once the card is on screen.
+		if _, height, err := term.GetSize(os.Stdout.Fd()); err == nil {
+			if card, draw, ok := overlayAvatar(renderProfile(p, repos, opts, true), opts.Avatar, opts.ImageProtocol, height); ok {
+				fmt.Print(card + draw)
+				return
 			}
-			b.WriteString(out)
 		}
 	}
+	fmt.Print(RenderProfile(p, repos, opts))
+}
 
-	sort.Slice(repos, func(i, j int) bool { return repos[i].StargazersCount > repos[j].StargazersCount })
-	if topN > len(repos) {
-		topN = len(repos)
+// RenderProfile returns the card PrintProfile prints, with the avatar, if any,
+// drawn in half blocks.
+func RenderProfile(p *github.Profile, repos []github.Repo, opts Options) string {
+	return renderProfile(p, repos, opts, false)
+}
+
+// renderProfile renders the card. With reserveAvatar the avatar's cells are
+// left blank for overlayAvatar instead of drawn.
+func renderProfile(p *github.Profile, repos []github.Repo, opts Options, reserveAvatar bool) string {
+	if p == nil {
+		return "No profile\n"
+	}
+	width := contentWidth(opts.Size, !opts.NoBorder && !opts.NoStyle)
+	c := &card{p: p, list: repos, opts: opts, width: width, layout: layoutFor(opts.Size, width), reserveAvatar: reserveAvatar}
+	return framePanel(c.render(), opts, width)
+}
+
+// framePanel cuts a card's lines to width and puts them in the panel, unless
+// opts turns styles or the border off.
+func framePanel(out string, opts Options, width int) string {
+	if opts.NoStyle {
+		return out
 	}
-	if topN > 0 {
-		b.WriteString("\n")
+	out = truncateLines(out, width)
+	if opts.NoBorder {
+		return out
+	}
+
+	panel := Panel(panelWidth(opts.Size)).Render(out)
+	return lipgloss.NewStyle().Margin(1, 2).Render(panel) + "\n"
+}
+
+// gistTitle returns the gist description, falling back to its first file name.
+func gistTitle(g github.Gist) string {
+	if g.Description != "" {
+		return g.Description
+	}
+	names := make([]string, 0, len(g.Files))
+	for name := range g.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) > 0 {
+		return names[0]
+	}
+	return g.ID
+}
+
+func starredSummary(label string, kvs []kv, noStyle bool) string {
+	parts := make([]string, len(kvs))
+	for i, x := range kvs {
 		if noStyle {
-			b.WriteString("Top repos:\n")
+			parts[i] = fmt.Sprintf("%s (%d)", x.k, x.v)
 		} else {
-			b.WriteString(Subtle.Render("Top repos:") + "\n")
-		}
-		for i := 0; i < topN; i++ {
-			r := repos[i]
-			langIcon := GetLangIcon(r.Language)
-			if noStyle {
-				b.WriteString(fmt.Sprintf("%d. %s %s ★ %d  %s %d\n", i+1, r.FullName, r.Language, r.StargazersCount, IconFork, r.ForksCount))
-				b.WriteString("  " + r.HTMLURL + "\n")
-			} else {
-				b.WriteString(fmt.Sprintf("%d. %s %s %s %d  %s %d\n", i+1, RepoTitle.Render(r.FullName), iconRender(langIcon), iconRender("★"), r.StargazersCount, iconRender(IconFork), r.ForksCount))
-				b.WriteString("  " + URLStyle.Render(r.HTMLURL) + "\n")
-			}
+			parts[i] = fmt.Sprintf("%s (%d)", Accent.Render(x.k), x.v)
 		}
 	}
+	if noStyle {
+		return label + " " + strings.Join(parts, ", ") + "\n"
+	}
+	return Subtle.Render(label) + " " + strings.Join(parts, ", ") + "\n"
+}
+
+// shortDate trims an RFC 3339 timestamp from the API down to its date.
+func shortDate(ts string) string {
+	if len(ts) >= 10 {
+		return ts[:10]
+	}
+	return ts
+}
 
-	out := b.String()
-	if noStyle || noBorder {
-		fmt.Print(out)
-		return
+// shortSHA abbreviates a commit hash the way GitHub shows it.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
 	}
+	return sha
+}
 
-	// Determine width from size flag. 0 means no width constraint (full).
-	width := 0
-	switch strings.ToLower(size) {
-	case "small":
-		width = 48
-	case "medium":
-		width = 88
-	case "large":
-		width = 120
-	case "full":
-		width = 0
-	default:
-		width = 88
+// calendarPeriod describes the range a contribution calendar covers.
+func calendarPeriod(cal *github.ContributionCalendar) string {
+	days := cal.Days()
+	if len(days) == 0 {
+		return ""
+	}
+	first, last := days[0].Date, days[len(days)-1].Date
+	if len(first) >= 4 && len(last) >= 4 && first[:4] == last[:4] {
+		return "in " + first[:4]
 	}
+	return "in the last year"
+}
 
-	panel := Panel(width).Render(out)
-	fmt.Println(lipgloss.NewStyle().Margin(1, 2).Render(panel))
+// plural formats n with word, adding an "s" unless n is 1.
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
 }
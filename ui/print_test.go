@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"ghprofile/github"
+)
+
+func TestRepoFilterOnlyAffectsTheList(t *testing.T) {
+	p := &github.Profile{Name: "octo"}
+	repos := []github.Repo{
+		{FullName: "octo/tool", Language: "Go", StargazersCount: 5},
+		{FullName: "octo/fork", Language: "Rust", StargazersCount: 9, Fork: true},
+	}
+	opts := Options{TopN: 5, NoStyle: true, Sections: []string{"languages", "repos"}, Filter: github.RepoFilter{NoForks: true}}
+	out := RenderProfile(p, repos, opts)
+	if !strings.Contains(out, "Rust") {
+		t.Errorf("languages lost the filtered repo:\n%s", out)
+	}
+	if strings.Contains(out, "octo/fork") || !strings.Contains(out, "octo/tool") {
+		t.Errorf("repo list ignores the filter:\n%s", out)
+	}
+}
+
+func TestMarkdownLanguagesFollowOptions(t *testing.T) {
+	p := &github.Profile{Name: "octo"}
+	repos := []github.Repo{
+		{FullName: "octo/a", Language: "Go", StargazersCount: 1},
+		{FullName: "octo/b", Language: "Go", StargazersCount: 1},
+		{FullName: "octo/c", Language: "Rust", StargazersCount: 8},
+		{FullName: "octo/d", Language: "C", StargazersCount: 1},
+	}
+	out := RenderMarkdown(p, repos, Options{TopLangs: 1})
+	if !strings.Contains(out, "- Go: 50.0% (2)\n- Other: 50.0% (2)\n") {
+		t.Errorf("expected the top language and Other by repos:\n%s", out)
+	}
+	out = RenderMarkdown(p, repos, Options{LangWeight: LangWeightStars})
+	if !strings.Contains(out, "## Languages (by stars)\n\n- Rust: 72.7% (8)\n") {
+		t.Errorf("expected languages by stars:\n%s", out)
+	}
+}
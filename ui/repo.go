@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"ghprofile/github"
+)
+
+// maxRepoCommits caps the commits listed on the repo card: all those fetched.
+const maxRepoCommits = github.MaxCommits
+
+// repoCard renders the sections of a repo card, sharing the profile card's
+// helpers. Its languages section is the card's, over the one repo.
+type repoCard struct {
+	*card
+	d *github.RepoDetail
+}
+
+// PrintRepo writes RenderRepo's output to stdout.
+func PrintRepo(d *github.RepoDetail, opts Options) {
+	fmt.Print(RenderRepo(d, opts))
+}
+
+// RenderRepo renders a repo card in the profile card's style: the repo, its
+// stats and languages, latest release, top contributors and recent commits.
+func RenderRepo(d *github.RepoDetail, opts Options) string {
+	if d == nil {
+		return "No repo\n"
+	}
+	width := contentWidth(opts.Size, !opts.NoBorder && !opts.NoStyle)
+	opts.LangBytes = true
+	c := &repoCard{
+		card: &card{list: []github.Repo{d.Repo}, opts: opts, width: width, layout: layoutFor(opts.Size, width)},
+		d:    d,
+	}
+	var parts []string
+	for _, s := range []string{c.header(), c.statsAndLanguages(), c.release(), c.contributors(), c.commits()} {
+		if strings.TrimSpace(s) != "" {
+			parts = append(parts, s)
+		}
+	}
+	return framePanel(strings.Join(parts, "\n"), opts, width)
+}
+
+func (c *repoCard) header() string {
+	d, noStyle := c.d, c.opts.NoStyle
+	var b strings.Builder
+	name, url := TitleStyle.Render(d.FullName), URLStyle.Render(d.HTMLURL)
+	if noStyle {
+		name, url = d.FullName, d.HTMLURL
+	}
+	if d.Private {
+		if lock := c.icon(c.icons().lock); lock != "" {
+			name = lock + " " + name
+		} else {
+			name += " (private)"
+		}
+	}
+	if icon := c.icon(c.icons().repo); icon != "" {
+		name = icon + "  " + name
+	}
+	b.WriteString(name + "  " + url + "\n")
+	if d.Description != "" {
+		if noStyle {
+			b.WriteString(d.Description + "\n")
+		} else {
+			b.WriteString(Subtle.Render(d.Description) + "\n")
+		}
+	}
+	if badges := repoBadges(d.Repo); len(badges) > 0 && c.layout != layoutCompact {
+		if noStyle {
+			b.WriteString("[" + strings.Join(badges, "] [") + "]\n")
+		} else {
+			rendered := make([]string, len(badges))
+			for i, badge := range badges {
+				rendered[i] = Badge.Render(badge)
+			}
+			b.WriteString(strings.Join(rendered, "") + "\n")
+		}
+	}
+	if d.Homepage != "" {
+		if noStyle {
+			b.WriteString(d.Homepage + "\n")
+		} else {
+			b.WriteString(c.icon(c.icons().link) + " " + URLStyle.Render(d.Homepage) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// statsAndLanguages puts the stats and languages side by side in the columns
+// layout and stacks them otherwise.
+func (c *repoCard) statsAndLanguages() string {
+	stats, langs := c.stats(), c.languages()
+	if c.layout == layoutColumns {
+		return sideBySide(stats, langs, c.width)
+	}
+	if stats == "" || langs == "" {
+		return stats + langs
+	}
+	return stats + "\n" + langs
+}
+
+func (c *repoCard) stats() string {
+	d := c.d
+	stats := []statEntry{
+		{c.icon(c.icons().star), "Stars:", fmt.Sprintf("%d", d.StargazersCount)},
+		{c.icon(c.icons().fork), "Forks:", fmt.Sprintf("%d", d.ForksCount)},
+		{c.icon(c.icons().followers), "Watchers:", fmt.Sprintf("%d", d.SubscribersCount)},
+	}
+	if d.OpenIssues != nil && d.OpenPulls != nil {
+		stats = append(stats,
+			statEntry{c.icon(c.icons().issue), "Open issues:", fmt.Sprintf("%d", *d.OpenIssues)},
+			statEntry{c.icon(c.icons().pr), "Open PRs:", fmt.Sprintf("%d", *d.OpenPulls)},
+		)
+	} else {
+		stats = append(stats, statEntry{c.icon(c.icons().issue), "Open issues+PRs:", fmt.Sprintf("%d", d.OpenIssuesCount)})
+	}
+	if d.DefaultBranch != "" {
+		stats = append(stats, statEntry{c.icon(c.icons().commit), "Branch:", d.DefaultBranch})
+	}
+	if d.CreatedAt != "" {
+		stats = append(stats, statEntry{c.icon(c.icons().joined), "Created:", shortDate(d.CreatedAt)})
+	}
+	if d.PushedAt != "" {
+		stats = append(stats, statEntry{c.icon(c.icons().activity), "Last push:", shortDate(d.PushedAt)})
+	}
+	var b strings.Builder
+	writeStats(&b, stats)
+	return b.String()
+}
+
+func (c *repoCard) release() string {
+	rel := c.d.LatestRelease
+	if rel == nil {
+		return ""
+	}
+	line := rel.TagName
+	if rel.Name != "" && rel.Name != rel.TagName {
+		line += " " + rel.Name
+	}
+	if !c.opts.NoStyle {
+		line = RepoTitle.Render(line)
+	}
+	if rel.Prerelease {
+		line += " (pre-release)"
+	}
+	if rel.PublishedAt != "" {
+		line += " · " + shortDate(rel.PublishedAt)
+	}
+	prefix := "   "
+	if icon := c.icon(c.icons().tag); icon != "" {
+		prefix = icon + "  "
+	}
+	return c.heading("Latest release:") + "\n" + prefix + line + "\n"
+}
+
+// contributors lists the top contributors, each with a bar of their commits
+// relative to the top one's.
+func (c *repoCard) contributors() string {
+	people := c.d.Contributors
+	if len(people) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(c.heading("Top contributors:") + "\n")
+	if c.layout == layoutCompact {
+		items := make([]string, len(people))
+		for i, p := range people {
+			items[i] = fmt.Sprintf("%s %d", c.login(p.Login), p.Contributions)
+		}
+		b.WriteString(strings.Join(items, " · ") + "\n")
+		return b.String()
+	}
+	nameWidth := 0
+	for _, p := range people {
+		nameWidth = max(nameWidth, len(p.Login))
+	}
+	top := people[0].Contributions
+	for i, p := range people {
+		row := fmt.Sprintf("%2d. %s%s  ", i+1, c.login(p.Login), strings.Repeat(" ", nameWidth-len(p.Login)))
+		row += shareBar(p.Contributions, top, langRowBarWidth, i, "", c.opts.NoStyle) + " "
+		row += c.count(p.Contributions) + "\n"
+		b.WriteString(row)
+	}
+	return b.String()
+}
+
+func (c *repoCard) login(login string) string {
+	if c.opts.NoStyle {
+		return login
+	}
+	return Accent.Render(login)
+}
+
+func (c *repoCard) commits() string {
+	commits := c.d.Commits
+	if len(commits) == 0 {
+		return ""
+	}
+	if len(commits) > maxRepoCommits {
+		commits = commits[:maxRepoCommits]
+	}
+	var b strings.Builder
+	b.WriteString(c.heading("Recent commits:") + "\n")
+	for _, cm := range commits {
+		sha := shortSHA(cm.SHA)
+		meta := cm.Author
+		if date := shortDate(cm.Date); date != "" {
+			meta += ", " + date
+		}
+		if c.opts.NoStyle {
+			b.WriteString(fmt.Sprintf("%s %s (%s)\n", sha, cm.Message, meta))
+			continue
+		}
+		b.WriteString(fmt.Sprintf("%s %s %s\n", RepoTitle.Render(sha), cm.Message, Subtle.Render("("+meta+")")))
+	}
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"ghprofile/github"
+)
+
+// testRepoDetail returns a repo with every optional part when full is set,
+// and with none of them, as when they fail to load, otherwise.
+func testRepoDetail(full bool) *github.RepoDetail {
+	d := &github.RepoDetail{Repo: github.Repo{
+		FullName: "acme/widget", HTMLURL: "https://github.com/acme/widget", Description: "A widget",
+		StargazersCount: 42, ForksCount: 3, SubscribersCount: 7, OpenIssuesCount: 5,
+		DefaultBranch: "main", Languages: map[string]int{"Go": 900, "Shell": 100},
+	}}
+	if !full {
+		return d
+	}
+	issues, pulls := 3, 2
+	d.OpenIssues, d.OpenPulls = &issues, &pulls
+	d.LatestRelease = &github.Release{TagName: "v1.2.0", HTMLURL: "https://github.com/acme/widget/releases/v1.2.0", PublishedAt: "2024-05-01T00:00:00Z"}
+	d.Contributors = []github.Contributor{{Login: "ada", Contributions: 120}, {Login: "bob", Contributions: 30}}
+	for i := range github.MaxCommits + 2 {
+		d.Commits = append(d.Commits, github.Commit{SHA: fmt.Sprintf("%07d0abcdef", i), Message: fmt.Sprintf("Commit %d", i), Author: "ada", Date: "2024-05-01T10:00:00Z"})
+	}
+	return d
+}
+
+// checkOutput reports each of want missing from out and each of missing found.
+func checkOutput(t *testing.T, name, out string, want, missing []string) {
+	t.Helper()
+	for _, s := range want {
+		if !strings.Contains(out, s) {
+			t.Errorf("%s: expected %q in:\n%s", name, s, out)
+		}
+	}
+	for _, s := range missing {
+		if strings.Contains(out, s) {
+			t.Errorf("%s: unexpected %q in:\n%s", name, s, out)
+		}
+	}
+}
+
+func TestRenderRepo(t *testing.T) {
+	opts := Options{NoStyle: true, TopLangs: 8}
+	lastCommit := fmt.Sprintf("Commit %d", github.MaxCommits-1)
+	hidden := fmt.Sprintf("Commit %d", github.MaxCommits)
+
+	full := RenderRepo(testRepoDetail(true), opts)
+	checkOutput(t, "full", full, []string{
+		"acme/widget  https://github.com/acme/widget", "A widget",
+		"Stars:", "Open issues: 3", "Open PRs:    2", "Branch:",
+		"Go", "90.0%", "Latest release:", "v1.2.0 · 2024-05-01",
+		"Top contributors:", " 1. ada", " 2. bob", "Recent commits:", "Commit 0", lastCommit,
+	}, []string{"Open issues+PRs:", hidden})
+
+	bare := RenderRepo(testRepoDetail(false), opts)
+	checkOutput(t, "bare", bare, []string{"acme/widget", "Open issues+PRs: 5", "Go"},
+		[]string{"Open PRs:", "Latest release:", "Top contributors:", "Recent commits:"})
+
+	if got := RenderRepo(nil, opts); got != "No repo\n" {
+		t.Errorf("RenderRepo(nil) = %q", got)
+	}
+}
+
+func TestRenderRepoMarkdown(t *testing.T) {
+	lastCommit := fmt.Sprintf("Commit %d", github.MaxCommits-1)
+	hidden := fmt.Sprintf("Commit %d", github.MaxCommits)
+
+	full := RenderRepoMarkdown(testRepoDetail(true), Options{TopLangs: 8})
+	checkOutput(t, "full", full, []string{
+		"# [acme/widget](https://github.com/acme/widget)\n\n> A widget\n",
+		"| Stars | 42 |", "| Open issues | 3 |\n| Open PRs | 2 |\n",
+		"- Go: 90.0%", "## Latest release\n\n[v1.2.0](https://github.com/acme/widget/releases/v1.2.0) · 2024-05-01\n",
+		"| [ada](", "| 120 |", "## Recent commits", "Commit 0", lastCommit,
+	}, []string{"Open issues+PRs", hidden})
+
+	bare := RenderRepoMarkdown(testRepoDetail(false), Options{TopLangs: 8})
+	checkOutput(t, "bare", bare, []string{"| Open issues+PRs | 5 |", "- Go: 90.0%"},
+		[]string{"| Open PRs |", "## Latest release", "## Top contributors", "## Recent commits"})
+}
@@ -0,0 +1,466 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ghprofile/github"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Sections lists the card's sections in their default order. Options.Sections
+// picks and reorders them.
+var Sections = []string{"header", "info", "stats", "contributions", "languages", "pinned", "repos", "activity", "gists", "starred"}
+
+// StatNames lists the rows of the stats section in their default order.
+// Options.Stats picks and reorders them. "private" is only shown for the
+// token owner.
+var StatNames = []string{"followers", "following", "repos", "private", "gists", "stars", "forks", "avg-stars"}
+
+// sectionRenderers render each of Sections. A renderer returns its lines, or ""
+// when it has nothing to show, and never starts with a blank line.
+var sectionRenderers = map[string]func(c *card) string{
+	"header":        (*card).header,
+	"info":          (*card).info,
+	"stats":         (*card).stats,
+	"contributions": (*card).contributions,
+	"languages":     (*card).languages,
+	"pinned":        (*card).pinned,
+	"repos":         (*card).repos,
+	"activity":      (*card).activity,
+	"gists":         (*card).gists,
+	"starred":       (*card).starred,
+}
+
+// card is what the section renderers of one profile card share.
+type card struct {
+	p     *github.Profile
+	list  []github.Repo
+	opts  Options
+	width int
+	// layout is how sections arrange themselves; see layoutFor.
+	layout cardLayout
+	// reserveAvatar leaves the avatar's cells blank for overlayAvatar.
+	reserveAvatar bool
+}
+
+// icons returns the card's icon set.
+func (c *card) icons() iconSet {
+	return c.opts.icons()
+}
+
+// icon renders s as an icon, or nothing when icons are off.
+func (c *card) icon(s string) string {
+	if !c.opts.ShowIcons || s == "" {
+		return ""
+	}
+	return IconStyle.Render(s)
+}
+
+// heading renders a section title.
+func (c *card) heading(s string) string {
+	if c.opts.NoStyle {
+		return s
+	}
+	return Subtle.Render(s)
+}
+
+// render joins the sections opts asks for, a blank line apart. In the columns
+// layout stats and languages share a row where the first of them is listed.
+func (c *card) render() string {
+	names := c.opts.Sections
+	if len(names) == 0 {
+		names = Sections
+	}
+	listed := map[string]bool{}
+	for _, name := range names {
+		listed[name] = true
+	}
+	pair := c.layout == layoutColumns && listed["stats"] && listed["languages"]
+
+	done := map[string]bool{}
+	var parts []string
+	for _, name := range names {
+		if done[name] {
+			continue
+		}
+		done[name] = true
+		var s string
+		switch render := sectionRenderers[name]; {
+		case pair && (name == "stats" || name == "languages"):
+			done["stats"], done["languages"] = true, true
+			s = sideBySide(c.stats(), c.languages(), c.width)
+		case render != nil:
+			s = render(c)
+		}
+		if strings.TrimSpace(s) != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func (c *card) header() string {
+	p := c.p
+	var header strings.Builder
+	title := TitleStyle.Render(fmt.Sprintf("%s", p.FullName))
+	url := URLStyle.Render(p.URL)
+	if c.icon(c.icons().user) != "" {
+		header.WriteString(c.icon(c.icons().user) + "  " + title + "  " + url + "\n")
+	} else {
+		header.WriteString(title + "  " + url + "\n")
+	}
+	if p.Bio != "" {
+		header.WriteString(Subtle.Render(p.Bio) + "\n")
+	}
+	if c.opts.Avatar == nil || c.opts.NoStyle {
+		return header.String()
+	}
+	picture := placeholder(avatarCols, avatarRows)
+	if !c.reserveAvatar {
+		picture = c.opts.Avatar.blocks(avatarCols, avatarRows)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, picture, "  ", strings.TrimSuffix(header.String(), "\n")) + "\n"
+}
+
+// info lists the profile's details, one per line, skipping empty ones.
+func (c *card) info() string {
+	p := c.p
+	var b strings.Builder
+	row := func(icon, value string) {
+		if value == "" {
+			return
+		}
+		if icon := c.icon(icon); icon != "" {
+			b.WriteString(icon + "  ")
+		} else {
+			b.WriteString("   ")
+		}
+		b.WriteString(value + "\n")
+	}
+	row(c.icons().company, p.Company)
+	row(c.icons().location, p.Location)
+	if p.Blog != "" {
+		if c.opts.NoStyle {
+			row(c.icons().link, p.Blog)
+		} else {
+			row(c.icons().link, URLStyle.Render(p.Blog))
+		}
+	}
+	if p.Twitter != "" {
+		row(c.icons().twitter, "@"+p.Twitter)
+	}
+	row(c.icons().email, p.Email)
+	if joined, ok := p.JoinedAt(); ok {
+		row(c.icons().joined, joinedAgo(joined, c.opts.now()))
+	}
+	if p.Hireable {
+		if c.opts.NoStyle {
+			row(c.icons().hireable, "[hireable]")
+		} else {
+			row(c.icons().hireable, strings.TrimRight(Badge.Render("hireable"), " "))
+		}
+	}
+	return b.String()
+}
+
+// joinedAgo describes when an account was created relative to now, e.g.
+// "Joined 9 years ago".
+func joinedAgo(joined, now time.Time) string {
+	days := int(now.Sub(joined).Hours() / 24)
+	if days < 1 {
+		return "Joined today"
+	}
+	return "Joined " + formatDays(days) + " ago"
+}
+
+// statEntries returns every stat row by name.
+func (c *card) statEntries() map[string]statEntry {
+	p, opts := c.p, c.opts
+	totalStars := 0
+	totalForks := 0
+	if p.TotalStars != nil {
+		totalStars = *p.TotalStars
+	}
+	if p.TotalForks != nil {
+		totalForks = *p.TotalForks
+	}
+	avg := float32(0)
+	if p.AvgStarsPerRepo != nil {
+		avg = *p.AvgStarsPerRepo
+	}
+
+	var followersDelta, starsDelta, forksDelta int
+	if ch := opts.Changes; ch != nil {
+		followersDelta, starsDelta, forksDelta = ch.FollowersDelta, ch.StarsDelta, ch.ForksDelta
+	}
+	return map[string]statEntry{
+		"followers": {c.icon(c.icons().followers), "Followers:", fmt.Sprintf("%d", p.FollowersAmount) + changeMark(followersDelta, opts)},
+		"following": {c.icon(c.icons().following), "Following:", fmt.Sprintf("%d", p.FollowingAmount)},
+		"repos":     {c.icon(c.icons().repo), "Public repos:", fmt.Sprintf("%d", p.PublicReposAmount)},
+		"private":   {c.icon(c.icons().lock), "Private repos:", privateRepos(p)},
+		"gists":     {c.icon(c.icons().gist), "Public gists:", fmt.Sprintf("%d", p.PublicGistsAmount)},
+		"stars":     {c.icon(c.icons().star), "Total stars:", fmt.Sprintf("%d", totalStars) + changeMark(starsDelta, opts)},
+		"forks":     {c.icon(c.icons().fork), "Total forks:", fmt.Sprintf("%d", totalForks) + changeMark(forksDelta, opts)},
+		"avg-stars": {c.icon(c.icons().star), "Avg stars/repo:", fmt.Sprintf("%.2f", avg)},
+	}
+}
+
+func (c *card) stats() string {
+	names := c.opts.Stats
+	if len(names) == 0 {
+		names = StatNames
+	}
+	entries := c.statEntries()
+	var stats []statEntry
+	for _, name := range names {
+		if name == "private" && !c.p.IsAuthenticatedUser {
+			continue
+		}
+		if e, ok := entries[name]; ok {
+			stats = append(stats, e)
+		}
+	}
+	var b strings.Builder
+	writeStats(&b, stats)
+	return b.String()
+}
+
+func (c *card) contributions() string {
+	cal := c.p.Contributions
+	if cal == nil || len(cal.Weeks) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(c.heading(fmt.Sprintf("%d contributions %s:", cal.TotalContributions, calendarPeriod(cal))) + "\n")
+	// The heatmap is a year of weeks wide; narrow cards only get the totals.
+	if heatmap := RenderHeatmap(cal, c.opts.NoStyle); c.layout != layoutCompact && (c.width == 0 || lipgloss.Width(heatmap) <= c.width) {
+		b.WriteString(heatmap)
+	}
+	current, longest := cal.Streaks()
+	writeStats(&b, []statEntry{
+		{c.icon(c.icons().activity), "Current streak:", plural(current, "day")},
+		{c.icon(c.icons().activity), "Longest streak:", plural(longest, "day")},
+	})
+	return b.String()
+}
+
+func (c *card) languages() string {
+	weights, heading, counted := c.languageWeights()
+	total := 0
+	for _, n := range weights {
+		total += n
+	}
+	if total == 0 {
+		return ""
+	}
+	ranked := groupOtherLanguages(rankCounts(weights, 0), c.opts.TopLangs)
+	barWidth := langBarWidth
+	if c.width > 0 && c.width < barWidth {
+		barWidth = c.width
+	}
+	var b strings.Builder
+	b.WriteString(c.heading(heading) + "\n")
+	b.WriteString(languageBar(ranked, total, barWidth, c.opts.NoStyle) + "\n")
+	if c.layout == layoutCompact {
+		items := make([]string, len(ranked))
+		for i, x := range ranked {
+			items[i] = fmt.Sprintf("%s %.0f%%", c.langName(x.k, i), float64(x.v)*100/float64(total))
+		}
+		b.WriteString(strings.Join(items, " · ") + "\n")
+		return b.String()
+	}
+	nameWidth := 0
+	for _, x := range ranked {
+		nameWidth = max(nameWidth, lipgloss.Width(x.k))
+	}
+	rowWidth := langRowBarWidth
+	if c.width > 0 {
+		// Leave room for the icon, name and percentage around the bar.
+		rowWidth = min(rowWidth, c.width-nameWidth-16)
+	}
+	for i, x := range ranked {
+		pct := fmt.Sprintf("%5.1f%%", float64(x.v)*100/float64(total))
+		name := c.langName(x.k, i) + strings.Repeat(" ", nameWidth-lipgloss.Width(x.k))
+		row := name + "  "
+		if rowWidth > 0 {
+			row += shareBar(x.v, total, rowWidth, i, x.k, c.opts.NoStyle) + " "
+		}
+		if c.opts.NoStyle {
+			row += pct
+		} else {
+			row += ValueStyle.Render(pct)
+		}
+		if counted {
+			row += "  " + c.count(x.v)
+		}
+		if c.opts.ShowIcons {
+			icon := c.icons().langIcon(x.k)
+			row = c.icon(icon) + strings.Repeat(" ", max(1, 3-lipgloss.Width(icon))) + row
+		}
+		b.WriteString(row + "\n")
+	}
+	return b.String()
+}
+
+// languageWeights returns what each language of the counted repos weighs, per
+// Options.LangBytes and Options.LangWeight, with the section's heading.
+// counted is false for bytes, which are only shown as percentages.
+func (c *card) languageWeights() (weights map[string]int, heading string, counted bool) {
+	repos := c.list
+	if c.p != nil {
+		repos = github.CountedRepos(c.p, repos)
+	}
+	if c.opts.LangBytes {
+		if byteCount := github.LanguageBytes(repos); len(byteCount) > 0 {
+			return byteCount, "Languages (by bytes):", false
+		}
+	}
+	if c.opts.LangWeight == LangWeightStars {
+		stars := map[string]int{}
+		for _, r := range repos {
+			if r.Language != "" && r.StargazersCount > 0 {
+				stars[r.Language] += r.StargazersCount
+			}
+		}
+		if len(stars) > 0 {
+			return stars, "Languages (by stars):", true
+		}
+	}
+	return repoLanguageCounts(repos), "Languages:", true
+}
+
+// privateRepos renders the private repo count with their stars and forks, which
+// the public totals leave out.
+func privateRepos(p *github.Profile) string {
+	s := fmt.Sprintf("%d", p.PrivateReposAmount)
+	if p.PrivateStars != nil && p.PrivateForks != nil {
+		s += fmt.Sprintf(" (%s, %s)", plural(*p.PrivateStars, "star"), plural(*p.PrivateForks, "fork"))
+	}
+	return s
+}
+
+// count renders a repo or star count next to a language's percentage.
+func (c *card) count(n int) string {
+	if c.opts.NoStyle {
+		return fmt.Sprint(n)
+	}
+	return Subtle.Render(fmt.Sprint(n))
+}
+
+// langName renders a language name in its colour.
+func (c *card) langName(name string, rank int) string {
+	if c.opts.NoStyle {
+		return name
+	}
+	if name == otherLanguages {
+		return Subtle.Render(name)
+	}
+	return langStyle(name, rank).Render(name)
+}
+
+func (c *card) pinned() string {
+	var b strings.Builder
+	writeRepoList(&b, "Pinned:", c.p.Pinned, len(c.p.Pinned), c.opts, c.layout, c.icon)
+	return strings.TrimPrefix(b.String(), "\n")
+}
+
+func (c *card) repos() string {
+	title, top, ok := repoListing(c.p, c.list, c.opts)
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	writeRepoList(&b, title, top, c.opts.TopN, c.opts, c.layout, c.icon)
+	return strings.TrimPrefix(b.String(), "\n")
+}
+
+func (c *card) activity() string {
+	if len(c.p.Events) == 0 && !c.opts.Activity {
+		return ""
+	}
+	act := github.SummarizeActivity(c.p.Events, c.opts.now().Add(-github.ActivityWindow))
+	var b strings.Builder
+	b.WriteString(c.heading(fmt.Sprintf("Activity (last %d days):", int(github.ActivityWindow.Hours()/24))) + "\n")
+	rows := []statEntry{
+		{c.icon(c.icons().commit), "Commits pushed:", fmt.Sprintf("%d", act.Commits)},
+		{c.icon(c.icons().pr), "PRs opened:", fmt.Sprintf("%d", act.PRsOpened)},
+		{c.icon(c.icons().pr), "PRs merged:", fmt.Sprintf("%d", act.PRsMerged)},
+		{c.icon(c.icons().issue), "Issues opened:", fmt.Sprintf("%d", act.IssuesOpened)},
+		{c.icon(c.icons().repo), "Repos created:", fmt.Sprintf("%d", act.ReposCreated)},
+		{c.icon(c.icons().tag), "Releases:", fmt.Sprintf("%d", act.Releases)},
+		{c.icon(c.icons().star), "Repos starred:", fmt.Sprintf("%d", act.StarsGiven)},
+	}
+	if top := rankCounts(act.EventsPerRepo, 1); len(top) > 0 {
+		rows = append(rows, statEntry{c.icon(c.icons().activity), "Most active in:", fmt.Sprintf("%s (%d events)", top[0].k, top[0].v)})
+	}
+	writeStats(&b, rows)
+	return b.String()
+}
+
+func (c *card) gists() string {
+	p := c.p
+	if len(p.Gists) == 0 {
+		return ""
+	}
+	// Only the latest gists are fetched; the profile counts them all.
+	var b strings.Builder
+	b.WriteString(c.heading(fmt.Sprintf("Gists (%d):", max(p.PublicGistsAmount, len(p.Gists)))) + "\n")
+	n := min(len(p.Gists), maxGists)
+	for i := 0; i < n; i++ {
+		g := p.Gists[i]
+		desc := gistTitle(g)
+		meta := fmt.Sprintf("%d files", g.FileCount())
+		if langs := g.Languages(); len(langs) > 0 {
+			meta += ", " + strings.Join(langs, ", ")
+		}
+		meta += fmt.Sprintf(", %d comments", g.Comments)
+		if c.opts.NoStyle {
+			b.WriteString(fmt.Sprintf("%d. %s (%s)\n", i+1, desc, meta))
+			if c.layout != layoutCompact {
+				b.WriteString("  " + g.HTMLURL + "\n")
+			}
+		} else {
+			b.WriteString(fmt.Sprintf("%d. %s %s %s\n", i+1, c.icon(c.icons().gist), RepoTitle.Render(desc), Subtle.Render("("+meta+")")))
+			if c.layout != layoutCompact {
+				b.WriteString("  " + URLStyle.Render(g.HTMLURL) + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+func (c *card) starred() string {
+	p, noStyle := c.p, c.opts.NoStyle
+	if len(p.Starred) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(c.heading("Recently starred:") + "\n")
+	n := min(len(p.Starred), maxStarred)
+	for i := 0; i < n; i++ {
+		r := p.Starred[i].Repo
+		when := shortDate(p.Starred[i].StarredAt)
+		if noStyle {
+			b.WriteString(fmt.Sprintf("%d. %s %s %s %d  %s\n", i+1, r.FullName, r.Language, c.icons().stargazers, r.StargazersCount, when))
+			if c.layout != layoutCompact {
+				b.WriteString("  " + r.HTMLURL + "\n")
+			}
+		} else {
+			b.WriteString(fmt.Sprintf("%d. %s %s %s %d  %s\n", i+1, RepoTitle.Render(r.FullName), repoLanguage(r.Language, c.icons(), c.opts.ShowIcons), c.icon(c.icons().stargazers), r.StargazersCount, Subtle.Render(when)))
+			if c.layout != layoutCompact {
+				b.WriteString("  " + URLStyle.Render(r.HTMLURL) + "\n")
+			}
+		}
+	}
+	st := github.CalcStarredStats(p.Starred)
+	if langs := rankCounts(st.Languages, maxStarredTags); len(langs) > 0 {
+		b.WriteString(starredSummary("Top starred languages:", langs, noStyle))
+	}
+	if topics := rankCounts(st.Topics, maxStarredTags); len(topics) > 0 {
+		b.WriteString(starredSummary("Top starred topics:", topics, noStyle))
+	}
+	return b.String()
+}
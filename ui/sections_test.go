@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ghprofile/github"
+)
+
+func testCard(layout cardLayout, sections, stats []string) *card {
+	stars := 7
+	p := &github.Profile{Name: "octo", FullName: "Octo Cat", FollowersAmount: 3, TotalStars: &stars}
+	repos := []github.Repo{{FullName: "octo/tool", Language: "Go", StargazersCount: 7}}
+	opts := Options{TopN: 5, NoStyle: true, Sections: sections, Stats: stats}
+	return &card{p: p, list: repos, opts: opts, width: 116, layout: layout}
+}
+
+func TestCardRenderSections(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		layout   cardLayout
+		sections []string
+		// want lists substrings in the order they must appear; each must
+		// appear exactly once.
+		want []string
+		// missing must not appear.
+		missing []string
+	}{
+		{"default order", layoutSingle, nil, []string{"Octo Cat", "Followers:", "Languages:", "octo/tool"}, nil},
+		{"listed order", layoutSingle, []string{"languages", "header"}, []string{"Languages:", "Octo Cat"}, []string{"Followers:", "octo/tool"}},
+		{"duplicates", layoutSingle, []string{"stats", "header", "stats"}, []string{"Followers:", "Octo Cat"}, []string{"Languages:"}},
+		{"paired in columns", layoutColumns, []string{"header", "stats", "languages"}, []string{"Octo Cat", "Followers:", "Languages:"}, nil},
+		{"pair at the first listed", layoutColumns, []string{"languages", "header", "stats"}, []string{"Followers:", "Languages:", "Octo Cat"}, nil},
+		{"unpaired alone", layoutColumns, []string{"stats"}, []string{"Followers:"}, []string{"Languages:"}},
+	} {
+		out := testCard(tc.layout, tc.sections, nil).render()
+		last := -1
+		for _, s := range tc.want {
+			i := strings.Index(out, s)
+			if i < 0 || strings.Count(out, s) != 1 {
+				t.Errorf("%s: expected %q exactly once:\n%s", tc.name, s, out)
+				continue
+			}
+			if i < last {
+				t.Errorf("%s: %q is out of order:\n%s", tc.name, s, out)
+			}
+			last = i
+		}
+		for _, s := range tc.missing {
+			if strings.Contains(out, s) {
+				t.Errorf("%s: unexpected %q:\n%s", tc.name, s, out)
+			}
+		}
+	}
+}
+
+func TestCardRenderPairsStatsAndLanguages(t *testing.T) {
+	sections := []string{"stats", "languages"}
+	sameLine := func(out string) bool {
+		for _, line := range strings.Split(out, "\n") {
+			if strings.Contains(line, "Followers:") && strings.Contains(line, "Languages:") {
+				return true
+			}
+		}
+		return false
+	}
+	if out := testCard(layoutColumns, sections, nil).render(); !sameLine(out) {
+		t.Errorf("expected stats and languages side by side:\n%s", out)
+	}
+	if out := testCard(layoutSingle, sections, nil).render(); sameLine(out) {
+		t.Errorf("expected stats and languages stacked:\n%s", out)
+	}
+}
+
+func TestCardStatsRows(t *testing.T) {
+	out := testCard(layoutSingle, []string{"stats"}, []string{"stars", "private", "followers"}).render()
+	stars, followers := strings.Index(out, "Total stars:"), strings.Index(out, "Followers:")
+	if stars < 0 || followers < stars || strings.Contains(out, "Following:") {
+		t.Errorf("expected stars then followers only:\n%s", out)
+	}
+	if strings.Contains(out, "Private repos:") {
+		t.Errorf("private repos shown for someone else's profile:\n%s", out)
+	}
+}
+
+func TestMarkdownFollowsSections(t *testing.T) {
+	c := testCard(layoutSingle, nil, nil)
+	opts := c.opts
+	opts.Sections, opts.Stats = []string{"languages", "stats", "header", "stats"}, []string{"stars"}
+	out := RenderMarkdown(c.p, c.list, opts)
+	if !strings.HasPrefix(out, "## Languages") {
+		t.Errorf("expected languages first:\n%s", out)
+	}
+	if strings.Count(out, "| Stat |") != 1 || !strings.Contains(out, "| Total stars | 7 |") || strings.Contains(out, "Followers") {
+		t.Errorf("expected one stats table with the listed rows:\n%s", out)
+	}
+	if strings.Index(out, "# [Octo Cat]") < strings.Index(out, "| Stat |") || strings.Contains(out, "octo/tool") {
+		t.Errorf("expected the header last and no repo list:\n%s", out)
+	}
+}
+
+func TestActivityWithoutEvents(t *testing.T) {
+	c := testCard(layoutSingle, []string{"activity"}, nil)
+	if out := c.render(); strings.Contains(out, "Activity") {
+		t.Errorf("activity shown without being fetched:\n%s", out)
+	}
+	c.opts.Activity = true
+	if out := c.render(); !strings.Contains(out, "Activity (last 30 days):") || !strings.Contains(out, "Commits pushed:") {
+		t.Errorf("expected the activity section with zero counts:\n%s", out)
+	}
+	if out := RenderMarkdown(c.p, c.list, c.opts); !strings.Contains(out, "## Activity") || !strings.Contains(out, "- Commits pushed: 0") {
+		t.Errorf("expected the markdown activity section with zero counts:\n%s", out)
+	}
+}
+
+func TestCardInfo(t *testing.T) {
+	c := testCard(layoutSingle, []string{"info"}, nil)
+	c.p.Location, c.p.Twitter, c.p.Hireable = "Berlin", "octo", true
+	c.p.MemberSince = "2020-03-01T00:00:00Z"
+	c.opts.Now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	want := "   Berlin\n   @octo\n   Joined 4 years ago\n   [hireable]\n"
+	if got := c.info(); got != want {
+		t.Errorf("info() = %q, want %q", got, want)
+	}
+	c.p.Location, c.p.Twitter, c.p.Hireable, c.p.MemberSince = "", "", false, ""
+	if got := c.info(); got != "" {
+		t.Errorf("expected no info lines for an empty profile, got %q", got)
+	}
+}
+
+func TestJoinedAgo(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		ago  time.Duration
+		want string
+	}{
+		{time.Hour, "Joined today"},
+		{36 * time.Hour, "Joined 1 day ago"},
+		{45 * 24 * time.Hour, "Joined 1 month ago"},
+		{800 * 24 * time.Hour, "Joined 2 years ago"},
+	} {
+		if got := joinedAgo(now.Add(-tc.ago), now); got != tc.want {
+			t.Errorf("joinedAgo(%v ago) = %q, want %q", tc.ago, got, tc.want)
+		}
+	}
+}
@@ -10,8 +10,32 @@ var (
 	accentCyan   = lipgloss.Color("#7dcfff")
 	accentBlue   = lipgloss.Color("#7aa2f7")
 	accentPurple = lipgloss.Color("#bb9af7")
+	// subtleFg is Subtle's colour, also used for the "Other" language bar.
+	subtleFg = lipgloss.Color("#9aa5ff")
 )
 
+// langPalette colours the segments of the language bar, in rank order.
+var langPalette = []lipgloss.Color{
+	accentCyan,
+	accentBlue,
+	accentPurple,
+	lipgloss.Color("#9ece6a"),
+	lipgloss.Color("#ff9e64"),
+	lipgloss.Color("#f7768e"),
+	lipgloss.Color("#e0af68"),
+	lipgloss.Color("#73daca"),
+}
+
+// heatColors shade contribution levels from none up to the fourth quartile,
+// ending on the green used for stat values.
+var heatColors = []lipgloss.Color{
+	headerBg,
+	lipgloss.Color("#34452f"),
+	lipgloss.Color("#566f41"),
+	lipgloss.Color("#7a9e55"),
+	lipgloss.Color("#9ece6a"),
+}
+
 // Panel returns a lipgloss.Style configured for the card panel.
 // width: if 0 then no width constraint (full width), otherwise sets Width(width).
 func Panel(width int) lipgloss.Style {
@@ -27,7 +51,7 @@ var (
 
 	URLStyle = lipgloss.NewStyle().Foreground(accentBlue).Underline(true)
 
-	Subtle = lipgloss.NewStyle().Foreground(lipgloss.Color("#9aa5ff"))
+	Subtle = lipgloss.NewStyle().Foreground(subtleFg)
 
 	StatStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#9ece6a"))
 
@@ -45,3 +69,21 @@ var (
 
 	Divider = lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7089"))
 )
+
+var (
+	gainStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#9ece6a"))
+	lossStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#f7768e"))
+)
+
+// deltaStyle colours a change green when positive, red when negative and
+// subtle when unchanged.
+func deltaStyle(delta int) lipgloss.Style {
+	switch {
+	case delta > 0:
+		return gainStyle
+	case delta < 0:
+		return lossStyle
+	default:
+		return Subtle
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"ghprofile/github"
+)
+
+const (
+	svgWidth = 495
+	// svgLanguages caps the languages shown in the SVG card's bar and legend.
+	svgLanguages = 6
+)
+
+// RenderSVG renders a compact stats card as a standalone SVG image, suitable for
+// embedding in a README.
+func RenderSVG(p *github.Profile, repos []github.Repo) string {
+	name := p.FullName
+	if name == "" {
+		name = p.Name
+	}
+	totalStars, totalForks := 0, 0
+	if p.TotalStars != nil {
+		totalStars = *p.TotalStars
+	}
+	if p.TotalForks != nil {
+		totalForks = *p.TotalForks
+	}
+	stats := []statEntry{
+		{"", "Followers", fmt.Sprintf("%d", p.FollowersAmount)},
+		{"", "Public repos", fmt.Sprintf("%d", p.PublicReposAmount)},
+		{"", "Total stars", fmt.Sprintf("%d", totalStars)},
+		{"", "Total forks", fmt.Sprintf("%d", totalForks)},
+	}
+
+	langs := rankCounts(repoLanguageCounts(github.CountedRepos(p, repos)), svgLanguages)
+	total := 0
+	for _, x := range langs {
+		total += x.v
+	}
+	legendRows := (len(langs) + 1) / 2
+	height := 70 + 25*len(stats)
+	if total > 0 {
+		height += 30 + 22*legendRows
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`+"\n",
+		svgWidth, height, svgWidth, height, svgText(name+"'s GitHub stats"))
+	b.WriteString(`<style>
+  .title { font: 600 18px 'Segoe UI', Ubuntu, sans-serif; fill: ` + string(accentCyan) + `; }
+  .label { font: 600 14px 'Segoe UI', Ubuntu, sans-serif; fill: ` + string(accentPurple) + `; }
+  .value { font: 700 14px 'Segoe UI', Ubuntu, sans-serif; fill: #9ece6a; }
+  .lang { font: 400 12px 'Segoe UI', Ubuntu, sans-serif; fill: ` + string(brightFg) + `; }
+</style>
+`)
+	fmt.Fprintf(&b, `<rect x="0.5" y="0.5" rx="6" width="%d" height="%d" fill="%s" stroke="%s"/>`+"\n",
+		svgWidth-1, height-1, cardBg, accentBlue)
+	fmt.Fprintf(&b, `<text x="25" y="35" class="title">%s</text>`+"\n", svgText(name))
+
+	y := 70
+	for _, s := range stats {
+		fmt.Fprintf(&b, `<text x="25" y="%d" class="label">%s:</text>`, y, svgText(s.label))
+		fmt.Fprintf(&b, `<text x="220" y="%d" class="value">%s</text>`+"\n", y, svgText(s.value))
+		y += 25
+	}
+
+	if total > 0 {
+		y += 5
+		barWidth := svgWidth - 50
+		fmt.Fprintf(&b, `<clipPath id="bar"><rect x="25" y="%d" width="%d" height="8" rx="4"/></clipPath>`+"\n", y, barWidth)
+		b.WriteString(`<g clip-path="url(#bar)">`)
+		x := 25.0
+		for i, l := range langs {
+			w := float64(barWidth) * float64(l.v) / float64(total)
+			fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="8" fill="%s"/>`, x, y, w, langColor(l.k, i))
+			x += w
+		}
+		b.WriteString("</g>\n")
+		y += 30
+		for i, l := range langs {
+			lx := 25 + (i%2)*220
+			ly := y + (i/2)*22
+			pct := float64(l.v) * 100 / float64(total)
+			fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="5" fill="%s"/>`, lx+5, ly-4, langColor(l.k, i))
+			fmt.Fprintf(&b, `<text x="%d" y="%d" class="lang">%s %.1f%%</text>`+"\n", lx+15, ly, svgText(l.k), pct)
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// svgText escapes s for use as SVG text or attribute content.
+func svgText(s string) string {
+	return html.EscapeString(s)
+}
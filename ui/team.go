@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"ghprofile/github"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+// maxTeamLanguages caps the team language totals; the rest are summed as "Other".
+const maxTeamLanguages = 10
+
+// leaderboardColumns lists the metric columns shown, dropping contributions
+// when none were fetched.
+func leaderboardColumns(members []github.Summary) []string {
+	cols := []string{"stars", "followers", "repos"}
+	for _, m := range members {
+		if m.Contributions > 0 {
+			return append(cols, "contributions")
+		}
+	}
+	return cols
+}
+
+// teamLanguageRows returns the team's top languages by repo count, with the
+// remainder folded into "Other".
+func teamLanguageRows(members []github.Summary) []kv {
+	ranked := rankCounts(github.TeamLanguages(members), 0)
+	if len(ranked) <= maxTeamLanguages {
+		return ranked
+	}
+	other := 0
+	for _, x := range ranked[maxTeamLanguages:] {
+		other += x.v
+	}
+	return append(ranked[:maxTeamLanguages], kv{"Other", other})
+}
+
+// RenderLeaderboard renders members, already ranked by metric by, as a table
+// followed by the team's language totals.
+func RenderLeaderboard(members []github.Summary, by string, noStyle bool) string {
+	cols := leaderboardColumns(members)
+	headers := []string{"#", "User"}
+	for _, c := range cols {
+		headers = append(headers, strings.ToUpper(c[:1])+c[1:])
+	}
+	headers = append(headers, "Top languages")
+	rankCol := -1
+	for i, c := range cols {
+		if c == by {
+			rankCol = i + 2
+		}
+	}
+
+	var rows [][]string
+	for i, m := range members {
+		row := []string{fmt.Sprintf("%d", i+1), m.Login}
+		for _, c := range cols {
+			row = append(row, fmt.Sprintf("%d", github.TeamMetric(m, c)))
+		}
+		rows = append(rows, append(row, strings.Join(m.TopLanguages, ", ")))
+	}
+
+	var b strings.Builder
+	if noStyle {
+		widths := make([]int, len(headers))
+		for _, r := range append([][]string{headers}, rows...) {
+			for i, c := range r {
+				widths[i] = max(widths[i], lipgloss.Width(c))
+			}
+		}
+		for _, r := range append([][]string{headers}, rows...) {
+			for i, c := range r {
+				b.WriteString(fmt.Sprintf("%-*s", widths[i]+2, c))
+			}
+			b.WriteString("\n")
+		}
+	} else {
+		t := table.New().
+			Border(lipgloss.RoundedBorder()).
+			BorderStyle(lipgloss.NewStyle().Foreground(accentBlue)).
+			Headers(headers...).
+			Rows(rows...).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				s := lipgloss.NewStyle().Padding(0, 1)
+				switch {
+				case row == table.HeaderRow:
+					return s.Inherit(TitleStyle)
+				case col == 1:
+					return s.Inherit(Accent)
+				case col == rankCol:
+					return s.Inherit(ValueStyle)
+				default:
+					return s.Foreground(brightFg)
+				}
+			})
+		b.WriteString(t.Render() + "\n")
+	}
+
+	if langs := teamLanguageRows(members); len(langs) > 0 {
+		b.WriteString("\n")
+		if noStyle {
+			b.WriteString("Team languages (repos):\n")
+		} else {
+			b.WriteString(Subtle.Render("Team languages (repos):") + "\n")
+		}
+		for _, x := range langs {
+			if noStyle {
+				b.WriteString(fmt.Sprintf("  %s: %d\n", x.k, x.v))
+			} else {
+				b.WriteString(fmt.Sprintf("  %s: %s\n", Accent.Render(x.k), ValueStyle.Render(fmt.Sprintf("%d", x.v))))
+			}
+		}
+	}
+	return b.String()
+}
+
+// RenderLeaderboardMarkdown renders the leaderboard and team languages as Markdown.
+func RenderLeaderboardMarkdown(members []github.Summary, by string) string {
+	cols := leaderboardColumns(members)
+	var b strings.Builder
+	b.WriteString("| # | User")
+	for _, c := range cols {
+		label := strings.ToUpper(c[:1]) + c[1:]
+		if c == by {
+			label = "**" + label + "**"
+		}
+		b.WriteString(" | " + label)
+	}
+	b.WriteString(" | Top languages |\n|---:|---" + strings.Repeat("|---:", len(cols)) + "|---|\n")
+	for i, m := range members {
+		fmt.Fprintf(&b, "| %d | [%s](https://github.com/%s)", i+1, m.Login, m.Login)
+		for _, c := range cols {
+			fmt.Fprintf(&b, " | %d", github.TeamMetric(m, c))
+		}
+		fmt.Fprintf(&b, " | %s |\n", mdEscape(strings.Join(m.TopLanguages, ", ")))
+	}
+	if langs := teamLanguageRows(members); len(langs) > 0 {
+		b.WriteString("\n## Team languages\n\n")
+		for _, x := range langs {
+			fmt.Fprintf(&b, "- %s: %d\n", x.k, x.v)
+		}
+	}
+	return b.String()
+}
+
+// RenderLeaderboardJSON renders the ranked members and the team's language totals.
+func RenderLeaderboardJSON(members []github.Summary, by string) ([]byte, error) {
+	out := struct {
+		RankedBy  string           `json:"ranked_by"`
+		Members   []github.Summary `json:"members"`
+		Languages map[string]int   `json:"languages"`
+	}{by, members, github.TeamLanguages(members)}
+	return json.MarshalIndent(out, "", "  ")
+}
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"ghprofile/github"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// FetchFunc loads the profile and the repos to show; Watch calls it on every refresh.
+type FetchFunc func() (*github.Profile, []github.Repo, error)
+
+const (
+	// flashTicks is how many times changed values blink after a refresh.
+	flashTicks    = 6
+	flashInterval = 500 * time.Millisecond
+)
+
+type watchModel struct {
+	fetch    FetchFunc
+	interval time.Duration
+	opts     Options
+	spinner  spinner.Model
+	fetching bool
+	profile  *github.Profile
+	repos    []github.Repo
+	last     *github.Snapshot
+	updated  time.Time
+	flashes  int
+	err      error
+	// gen counts refreshes so ticks scheduled before a manual refresh are dropped.
+	gen int
+}
+
+type watchTickMsg struct{ gen int }
+
+type watchFlashMsg struct{ gen int }
+
+type watchResult struct {
+	profile *github.Profile
+	repos   []github.Repo
+	err     error
+}
+
+// Watch shows the card on the alternate screen and refreshes it every interval
+// until the user quits. Values that changed since the previous refresh are
+// marked and briefly flash.
+func Watch(fetch FetchFunc, interval time.Duration, opts Options) error {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	m := &watchModel{fetch: fetch, interval: interval, opts: opts, spinner: s, fetching: true}
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m *watchModel) fetchCmd() tea.Cmd {
+	return func() tea.Msg {
+		p, repos, err := m.fetch()
+		return watchResult{profile: p, repos: repos, err: err}
+	}
+}
+
+func (m *watchModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.fetchCmd())
+}
+
+func (m *watchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "r":
+			if !m.fetching {
+				m.fetching = true
+				return m, tea.Batch(m.spinner.Tick, m.fetchCmd())
+			}
+		}
+	case spinner.TickMsg:
+		if !m.fetching {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case watchTickMsg:
+		if m.fetching || msg.gen != m.gen {
+			return m, nil
+		}
+		m.fetching = true
+		return m, tea.Batch(m.spinner.Tick, m.fetchCmd())
+	case watchFlashMsg:
+		if m.flashes > 0 && msg.gen == m.gen {
+			m.flashes--
+			return m, m.flashCmd()
+		}
+	case watchResult:
+		m.fetching = false
+		m.err = msg.err
+		m.gen++
+		gen := m.gen
+		next := tea.Tick(m.interval, func(time.Time) tea.Msg { return watchTickMsg{gen: gen} })
+		if msg.err != nil {
+			return m, next
+		}
+		m.profile, m.repos = msg.profile, msg.repos
+		m.updated = time.Now()
+		snap := github.NewSnapshot(m.profile, m.repos, m.updated)
+		m.opts.Changes = nil
+		if m.last != nil {
+			if d := github.DiffSnapshots(*m.last, snap); hasChanges(d) {
+				m.opts.Changes = &d
+				m.flashes = flashTicks
+				m.last = &snap
+				return m, tea.Batch(next, m.flashCmd())
+			}
+		}
+		m.last = &snap
+		return m, next
+	}
+	return m, nil
+}
+
+func (m *watchModel) flashCmd() tea.Cmd {
+	gen := m.gen
+	return tea.Tick(flashInterval, func(time.Time) tea.Msg { return watchFlashMsg{gen: gen} })
+}
+
+// hasChanges reports whether d has anything RenderProfile would mark.
+func hasChanges(d github.SnapshotDiff) bool {
+	return d.FollowersDelta != 0 || d.StarsDelta != 0 || d.ForksDelta != 0 || len(d.RepoDeltas) > 0
+}
+
+func (m *watchModel) View() string {
+	if m.profile == nil {
+		if m.err != nil {
+			return Panel(88).Render(fmt.Sprintf("Error: %v\nRetrying in %s · q quit", m.err, m.interval))
+		}
+		return Panel(88).Render(fmt.Sprintf("%s Loading %s", TitleStyle.Render("ghprofile"), m.spinner.View()))
+	}
+	opts := m.opts
+	opts.Flash = m.flashes%2 == 1
+	status := fmt.Sprintf("Updated %s · every %s · r refresh · q quit", m.updated.Format("15:04:05"), m.interval)
+	if m.fetching {
+		status = m.spinner.View() + " refreshing… · " + status
+	}
+	if m.err != nil {
+		status = fmt.Sprintf("refresh failed: %v · %s", m.err, status)
+	}
+	if !opts.NoStyle {
+		status = Subtle.Render(status)
+	}
+	return RenderProfile(m.profile, m.repos, opts) + "  " + status
+}